@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"cppcho.com/ynab_import/encoding"
+	"cppcho.com/ynab_import/parsers"
+)
+
+// Rows of the file shown by the detect command
+const detectPreviewRows = 5
+
+// explainDetection prints the encoding and first rows of filePath, and why
+// each registered parser did or did not match it
+func explainDetection(w io.Writer, filePath string) error {
+	if isPDF(filePath) {
+		return explainPDFDetection(w, filePath)
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read CSV: %w", err)
+	}
+	defer f.Close()
+	csvReader, charset, err := encoding.NewCSVReader(f, csvEncoding)
+	if err != nil {
+		return fmt.Errorf("failed to read CSV: %w", err)
+	}
+	// Detection only sees the first rows, as when importing
+	records, rest, err := parsers.Peek(csvReader, parsers.DetectRows)
+	if err != nil {
+		return fmt.Errorf("failed to read CSV: %w", err)
+	}
+	rowCount, err := countRows(rest)
+	if err != nil {
+		return fmt.Errorf("failed to read CSV: %w", err)
+	}
+
+	fmt.Fprintf(w, "Encoding: %s\n", charset)
+	fmt.Fprintf(w, "Rows: %d\n", rowCount)
+	for i, row := range records[:min(detectPreviewRows, len(records))] {
+		fmt.Fprintf(w, "  %d: %q\n", i+1, row)
+	}
+
+	fmt.Fprintf(w, "\nParsers:\n")
+	for _, parser := range registry {
+		printDetection(w, parser.Name(), parsers.SafeDetect(parser, records))
+	}
+
+	var ranked []string
+	for _, candidate := range parsers.Rank(registry, records) {
+		ranked = append(ranked, candidate.Parser.Name())
+	}
+	printOutcome(w, ranked)
+	return nil
+}
+
+// countRows reads the remaining rows without keeping them
+func countRows(rows parsers.RowReader) (int, error) {
+	count := 0
+	for {
+		_, err := rows.Read()
+		if errors.Is(err, io.EOF) {
+			return count, nil
+		}
+		if err != nil {
+			return count, fmt.Errorf("failed to read row %d: %w", count+1, err)
+		}
+		count++
+	}
+}
+
+// explainPDFDetection is explainDetection for the text of a PDF
+func explainPDFDetection(w io.Writer, filePath string) error {
+	text, err := pdfExtractor.Extract(filePath)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Extractor: %s\n", pdfExtractor.Name())
+
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	fmt.Fprintf(w, "Lines: %d\n", len(lines))
+	for i, line := range lines[:min(detectPreviewRows, len(lines))] {
+		fmt.Fprintf(w, "  %d: %s\n", i+1, line)
+	}
+
+	fmt.Fprintf(w, "\nParsers:\n")
+	for _, parser := range pdfRegistry {
+		printDetection(w, parser.Name(), parsers.SafeDetectPDF(parser, text))
+	}
+
+	var ranked []string
+	for _, candidate := range parsers.RankPDF(pdfRegistry, text) {
+		ranked = append(ranked, candidate.Parser.Name())
+	}
+	printOutcome(w, ranked)
+	return nil
+}
+
+func printDetection(w io.Writer, name string, detection parsers.Detection) {
+	if detection.Score == parsers.ScoreNone {
+		fmt.Fprintf(w, "  %-13s rejected: %s\n", name, detection.Reason)
+	} else {
+		fmt.Fprintf(w, "  %-13s matched (score %d)\n", name, detection.Score)
+	}
+	for _, detail := range detection.Details {
+		fmt.Fprintf(w, "  %-13s   %s\n", "", detail)
+	}
+}
+
+// printOutcome names the parser that would be used, ranked best first
+func printOutcome(w io.Writer, ranked []string) {
+	switch {
+	case len(ranked) == 0:
+		fmt.Fprintf(w, "\nNo parser matched\n")
+	case len(ranked) > 1:
+		fmt.Fprintf(w, "\nAmbiguous: %d parsers matched, %s would be used (use -parser to choose)\n", len(ranked), ranked[0])
+	default:
+		fmt.Fprintf(w, "\n%s would be used\n", ranked[0])
+	}
+}
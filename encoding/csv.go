@@ -0,0 +1,99 @@
+// Package encoding reads bank and card exports into raw CSV rows,
+// converting CP932, EUC-JP and UTF-16 files to UTF-8 on the way
+package encoding
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"os"
+
+	"golang.org/x/text/transform"
+)
+
+// ReadCSVFile reads the CSV file at path, see ReadCSV
+func ReadCSVFile(path string) ([][]string, error) {
+	records, _, err := ReadCSVFileAs(path, "")
+	return records, err
+}
+
+// ReadCSVFileAs reads the CSV file at path, see ReadCSVAs
+func ReadCSVFileAs(path, encodingName string) ([][]string, Charset, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, Charset{}, err
+	}
+	defer f.Close()
+	return ReadCSVAs(f, encodingName)
+}
+
+// ReadCSV reads all rows from r, detecting the encoding. Rows may have
+// different lengths and quotes are parsed leniently.
+func ReadCSV(r io.Reader) ([][]string, error) {
+	records, _, err := ReadCSVAs(r, "")
+	return records, err
+}
+
+// ReadCSVAs is ReadCSV in the given encoding (detected when empty) and
+// also returns the encoding the rows were decoded from
+func ReadCSVAs(r io.Reader, encodingName string) ([][]string, Charset, error) {
+	csvReader, charset, err := NewCSVReader(r, encodingName)
+	if err != nil {
+		return nil, Charset{}, err
+	}
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, charset, err
+	}
+	return records, charset, nil
+}
+
+// Bytes at the start of a stream that DetectCharset looks at
+const sampleSize = 64 * 1024
+
+// NewCSVReader returns a reader of the rows of r one at a time, decoded to
+// UTF-8 from the given encoding or, when empty, the encoding detected in
+// the first 64KB. Like ReadCSV rows may have different lengths.
+func NewCSVReader(r io.Reader, encodingName string) (*csv.Reader, Charset, error) {
+	name, err := LookupEncoding(encodingName)
+	if err != nil {
+		return nil, Charset{}, err
+	}
+
+	buffered := bufio.NewReaderSize(r, sampleSize)
+	charset := Charset{Name: name, Forced: true}
+	if name == "" {
+		sample, err := buffered.Peek(sampleSize)
+		switch {
+		case err == nil:
+			// More follows, end the sample at a line break so no character is cut
+			if end := bytes.LastIndexByte(sample, '\n'); end >= 0 {
+				sample = sample[:end+1]
+			}
+		case err != io.EOF:
+			return nil, Charset{}, err
+		}
+		if charset, err = DetectCharset(sample); err != nil {
+			return nil, Charset{}, err
+		}
+	}
+
+	var decoded io.Reader = buffered
+	if charset.Name != UTF8 {
+		decoded = transform.NewReader(buffered, decoders[charset.Name].NewDecoder())
+	}
+	csvReader := csv.NewReader(skipBOM(decoded))
+	csvReader.FieldsPerRecord = -1
+	csvReader.LazyQuotes = true
+	return csvReader, charset, nil
+}
+
+// skipBOM drops the byte order mark at the start of decoded text
+func skipBOM(r io.Reader) io.Reader {
+	buffered := bufio.NewReader(r)
+	if ch, _, err := buffered.ReadRune(); err == nil && ch != '\uFEFF' {
+		buffered.UnreadRune()
+	}
+	return buffered
+}
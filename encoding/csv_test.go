@@ -0,0 +1,175 @@
+package encoding
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestReadCSVFile_UTF8(t *testing.T) {
+	records, err := ReadCSVFile("testdata/utf8_simple.csv")
+	if err != nil {
+		t.Fatalf("ReadCSVFile() error = %v", err)
+	}
+
+	if len(records) != 4 {
+		t.Errorf("ReadCSVFile() got %d records, want 4", len(records))
+	}
+
+	// Check header
+	expectedHeader := []string{"日付", "金額", "内容"}
+	if len(records) > 0 {
+		for i, h := range expectedHeader {
+			if records[0][i] != h {
+				t.Errorf("Header[%d] = %q, want %q", i, records[0][i], h)
+			}
+		}
+	}
+}
+
+func TestReadCSVFile_ShiftJIS(t *testing.T) {
+	// Note: This test uses one of the real Shift_JIS samples for accurate testing
+	// The small synthetic file may not have enough data for reliable encoding detection
+	records, err := ReadCSVFile("../parsers/testdata/epos_valid.csv")
+	if err != nil {
+		t.Fatalf("ReadCSVFile() error = %v", err)
+	}
+
+	if len(records) < 1 {
+		t.Fatal("ReadCSVFile() returned no records")
+	}
+
+	// Verify Shift_JIS was properly decoded to UTF-8
+	// If encoding detection failed, Japanese characters would be garbled
+	// Epos header starts with: 種別（ショッピング、キャッシング、その他）
+	if len(records[0]) > 0 && !containsJapanese(records[0][0]) {
+		t.Errorf("Shift_JIS decoding may have failed: got %q", records[0][0])
+	}
+}
+
+// Helper function to check if string contains Japanese characters
+func containsJapanese(s string) bool {
+	for _, r := range s {
+		if (r >= 0x3040 && r <= 0x309F) || // Hiragana
+			(r >= 0x30A0 && r <= 0x30FF) || // Katakana
+			(r >= 0x4E00 && r <= 0x9FAF) { // Kanji
+			return true
+		}
+	}
+	return false
+}
+
+func TestReadCSVFile_Empty(t *testing.T) {
+	records, err := ReadCSVFile("testdata/empty.csv")
+	if err != nil {
+		t.Fatalf("ReadCSVFile() error = %v", err)
+	}
+
+	if len(records) != 0 {
+		t.Errorf("ReadCSVFile() got %d records, want 0", len(records))
+	}
+}
+
+func TestReadCSVFile_Malformed(t *testing.T) {
+	// With LazyQuotes = true, malformed CSVs should still be read
+	records, err := ReadCSVFile("testdata/malformed.csv")
+	if err != nil {
+		t.Fatalf("ReadCSVFile() unexpected error = %v", err)
+	}
+
+	// Should have at least the header row
+	if len(records) < 1 {
+		t.Errorf("ReadCSVFile() got %d records, want at least 1", len(records))
+	}
+}
+
+func TestReadCSVFile_FileNotFound(t *testing.T) {
+	_, err := ReadCSVFile("testdata/does_not_exist.csv")
+	if err == nil {
+		t.Error("ReadCSVFile() expected error for non-existent file, got nil")
+	}
+}
+
+func TestDetectCharset(t *testing.T) {
+	tests := []struct {
+		file     string
+		expected string
+	}{
+		{"testdata/utf8_simple.csv", "UTF-8"},
+		{"../parsers/testdata/epos_valid.csv", CP932},
+	}
+
+	for _, tt := range tests {
+		data, err := os.ReadFile(tt.file)
+		if err != nil {
+			t.Fatalf("ReadFile() error = %v", err)
+		}
+		charset, err := DetectCharset(data)
+		if err != nil {
+			t.Fatalf("DetectCharset() error = %v", err)
+		}
+		if charset.Name != tt.expected {
+			t.Errorf("DetectCharset(%s) = %q, want %q", tt.file, charset.Name, tt.expected)
+		}
+	}
+}
+
+func TestNewCSVReader_Streams(t *testing.T) {
+	// Several times the detection sample, the charset must come from its start
+	var text strings.Builder
+	text.WriteString("ご利用日,ご利用店名,金額\n")
+	rows := 3 * sampleSize / len("2025/12/23,㈱テスト①髙島屋,1234\n")
+	for i := 0; i < rows; i++ {
+		text.WriteString("2025/12/23,㈱テスト①髙島屋,1234\n")
+	}
+
+	for _, name := range []string{CP932, EUCJP, UTF16LE, UTF16BE} {
+		t.Run(name, func(t *testing.T) {
+			source := text.String()
+			if name == EUCJP {
+				source = strings.ReplaceAll(source, "㈱テスト①髙島屋", "テスト高島屋")
+			}
+			reader, charset, err := NewCSVReader(bytes.NewReader(encode(t, name, source)), "")
+			if err != nil {
+				t.Fatalf("NewCSVReader() error = %v", err)
+			}
+			if charset.Name != name {
+				t.Errorf("charset = %v, want %s", charset, name)
+			}
+
+			count := 0
+			for {
+				row, err := reader.Read()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("Read() error = %v", err)
+				}
+				if count == 0 && row[0] != "ご利用日" {
+					t.Errorf("header = %q", row)
+				}
+				count++
+			}
+			if count != rows+1 {
+				t.Errorf("read %d rows, want %d", count, rows+1)
+			}
+		})
+	}
+}
+
+func TestNewCSVReader_StripsBOM(t *testing.T) {
+	reader, charset, err := NewCSVReader(strings.NewReader("\uFEFF取引日,金額\n"), "")
+	if err != nil {
+		t.Fatalf("NewCSVReader() error = %v", err)
+	}
+	row, err := reader.Read()
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if charset != (Charset{Name: UTF8, BOM: true}) || row[0] != "取引日" {
+		t.Errorf("NewCSVReader() = %v, %q, want UTF-8 (BOM) without the BOM", charset, row)
+	}
+}
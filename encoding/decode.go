@@ -0,0 +1,194 @@
+package encoding
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/saintfish/chardet"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// Encodings of Japanese exports, as reported per file and accepted by -encoding
+const (
+	UTF8    = "UTF-8"
+	CP932   = "CP932" // Shift_JIS with the Windows extensions (①, ㈱, NEC and IBM kanji)
+	EUCJP   = "EUC-JP"
+	UTF16LE = "UTF-16LE"
+	UTF16BE = "UTF-16BE"
+)
+
+// Encodings lists the supported encodings
+var Encodings = []string{UTF8, CP932, EUCJP, UTF16LE, UTF16BE}
+
+var decoders = map[string]encoding.Encoding{
+	UTF8:    unicode.UTF8,
+	CP932:   japanese.ShiftJIS, // x/text's Shift_JIS is the Windows-31J table
+	EUCJP:   japanese.EUCJP,
+	UTF16LE: unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM),
+	UTF16BE: unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM),
+}
+
+// Other names of the supported encodings, lowercase
+var encodingAliases = map[string]string{
+	"utf8":        UTF8,
+	"shift_jis":   CP932,
+	"shift-jis":   CP932,
+	"sjis":        CP932,
+	"windows-31j": CP932,
+	"ms932":       CP932,
+	"eucjp":       EUCJP,
+	"utf16le":     UTF16LE,
+	"utf16be":     UTF16BE,
+}
+
+var boms = []struct {
+	prefix   []byte
+	encoding string
+}{
+	{[]byte{0xEF, 0xBB, 0xBF}, UTF8},
+	{[]byte{0xFF, 0xFE}, UTF16LE},
+	{[]byte{0xFE, 0xFF}, UTF16BE},
+}
+
+// Charset is the encoding a file was read with
+type Charset struct {
+	Name   string // One of Encodings
+	BOM    bool   // The file started with a byte order mark
+	Forced bool   // Set with -encoding instead of detected
+}
+
+// String is the name with how it was chosen, e.g. "UTF-8 (BOM)"
+func (c Charset) String() string {
+	switch {
+	case c.Forced:
+		return c.Name + " (forced)"
+	case c.BOM:
+		return c.Name + " (BOM)"
+	default:
+		return c.Name
+	}
+}
+
+// LookupEncoding resolves an -encoding value such as cp932, Shift_JIS or
+// utf-16le. The empty name means detect.
+func LookupEncoding(name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+	for _, known := range Encodings {
+		if strings.EqualFold(name, known) {
+			return known, nil
+		}
+	}
+	if known, ok := encodingAliases[strings.ToLower(name)]; ok {
+		return known, nil
+	}
+	return "", fmt.Errorf("unknown encoding %q (want one of %s)", name, strings.Join(Encodings, ", "))
+}
+
+// DetectCharset picks the encoding of data: a byte order mark wins, then
+// UTF-16 recognised by its zero bytes, then valid UTF-8, and otherwise the
+// Japanese legacy encoding that decodes with fewest errors
+func DetectCharset(data []byte) (Charset, error) {
+	for _, bom := range boms {
+		if bytes.HasPrefix(data, bom.prefix) {
+			return Charset{Name: bom.encoding, BOM: true}, nil
+		}
+	}
+	// Before UTF-8, as the zero bytes of UTF-16 ASCII are valid UTF-8
+	if name := detectUTF16(data); name != "" {
+		return Charset{Name: name}, nil
+	}
+	if utf8.Valid(data) {
+		return Charset{Name: UTF8}, nil
+	}
+
+	cp932, err := decoders[CP932].NewDecoder().Bytes(data)
+	if err != nil {
+		return Charset{}, err
+	}
+	eucjp, err := decoders[EUCJP].NewDecoder().Bytes(data)
+	if err != nil {
+		return Charset{}, err
+	}
+	cp932Errors, eucjpErrors := bytes.Count(cp932, []byte("\uFFFD")), bytes.Count(eucjp, []byte("\uFFFD"))
+	switch {
+	case cp932Errors < eucjpErrors:
+		return Charset{Name: CP932}, nil
+	case eucjpErrors < cp932Errors:
+		return Charset{Name: EUCJP}, nil
+	}
+
+	// EUC-JP kanji also read as half-width katakana in CP932, ask chardet
+	if result, err := chardet.NewTextDetector().DetectBest(data); err == nil && result.Charset == "EUC-JP" {
+		return Charset{Name: EUCJP}, nil
+	}
+	if countHalfWidthKana(eucjp) < countHalfWidthKana(cp932) {
+		return Charset{Name: EUCJP}, nil
+	}
+	return Charset{Name: CP932}, nil
+}
+
+// detectUTF16 recognises UTF-16 without a BOM by the zero high bytes of
+// its ASCII digits, commas and line breaks. A trailing odd byte is ignored,
+// as a sample cut after the line break of UTF-16LE ends in one.
+func detectUTF16(data []byte) string {
+	if len(data) < 4 {
+		return ""
+	}
+	var evenZeros, oddZeros int
+	for i := 0; i+1 < len(data); i += 2 {
+		if data[i] == 0 {
+			evenZeros++
+		}
+		if data[i+1] == 0 {
+			oddZeros++
+		}
+	}
+	pairs := len(data) / 2
+	switch {
+	case oddZeros*4 >= pairs && evenZeros == 0:
+		return UTF16LE
+	case evenZeros*4 >= pairs && oddZeros == 0:
+		return UTF16BE
+	}
+	return ""
+}
+
+func countHalfWidthKana(text []byte) int {
+	count := 0
+	for _, r := range string(text) {
+		if r >= 0xFF61 && r <= 0xFF9F {
+			count++
+		}
+	}
+	return count
+}
+
+// Decode converts data to UTF-8 and strips the byte order mark. name forces
+// an encoding (see LookupEncoding); when empty the encoding is detected.
+func Decode(data []byte, name string) ([]byte, Charset, error) {
+	name, err := LookupEncoding(name)
+	if err != nil {
+		return nil, Charset{}, err
+	}
+
+	charset := Charset{Name: name, Forced: true}
+	if name == "" {
+		if charset, err = DetectCharset(data); err != nil {
+			return nil, Charset{}, err
+		}
+	}
+
+	decoded := data
+	if charset.Name != UTF8 {
+		if decoded, err = decoders[charset.Name].NewDecoder().Bytes(data); err != nil {
+			return nil, Charset{}, fmt.Errorf("failed to decode %s: %w", charset.Name, err)
+		}
+	}
+	return bytes.TrimPrefix(decoded, []byte("\uFEFF")), charset, nil
+}
@@ -0,0 +1,127 @@
+package encoding
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// A card statement row with CP932-only characters: circled numbers, ㈱ and
+// an NEC selected IBM kanji
+const sampleCSV = "ご利用日,ご利用店名,金額\n2025/12/23,㈱テスト①髙島屋,1234\n"
+
+// EUC-JP has none of the CP932 extensions
+const eucSampleCSV = "ご利用日,ご利用店名,金額\n2025/12/23,テスト高島屋,1234\n"
+
+func encode(t *testing.T, name, text string) []byte {
+	t.Helper()
+	data, err := decoders[name].NewEncoder().Bytes([]byte(text))
+	if err != nil {
+		t.Fatalf("encoding to %s: %v", name, err)
+	}
+	return data
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected Charset
+	}{
+		{"utf-8", []byte(sampleCSV), Charset{Name: UTF8}},
+		{"utf-8 bom", append([]byte{0xEF, 0xBB, 0xBF}, sampleCSV...), Charset{Name: UTF8, BOM: true}},
+		{"cp932", encode(t, CP932, sampleCSV), Charset{Name: CP932}},
+		{"euc-jp", encode(t, EUCJP, eucSampleCSV), Charset{Name: EUCJP}},
+		{"utf-16le", encode(t, UTF16LE, sampleCSV), Charset{Name: UTF16LE}},
+		{"utf-16be", encode(t, UTF16BE, sampleCSV), Charset{Name: UTF16BE}},
+		{"utf-16le bom", append([]byte{0xFF, 0xFE}, encode(t, UTF16LE, sampleCSV)...), Charset{Name: UTF16LE, BOM: true}},
+		{"utf-16be bom", append([]byte{0xFE, 0xFF}, encode(t, UTF16BE, sampleCSV)...), Charset{Name: UTF16BE, BOM: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, charset, err := Decode(tt.data, "")
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if charset != tt.expected {
+				t.Errorf("Decode() charset = %+v, want %+v", charset, tt.expected)
+			}
+			want := sampleCSV
+			if tt.expected.Name == EUCJP {
+				want = eucSampleCSV
+			}
+			if string(decoded) != want {
+				t.Errorf("Decode() = %q, want %q", decoded, want)
+			}
+		})
+	}
+}
+
+func TestDecode_Forced(t *testing.T) {
+	// Too short to tell EUC-JP from CP932 half-width katakana
+	data, err := japanese.EUCJP.NewEncoder().Bytes([]byte("残高"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, charset, err := Decode(data, "euc-jp")
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if string(decoded) != "残高" || charset != (Charset{Name: EUCJP, Forced: true}) {
+		t.Errorf("Decode() = %q, %+v", decoded, charset)
+	}
+	if charset.String() != "EUC-JP (forced)" {
+		t.Errorf("String() = %q, want %q", charset.String(), "EUC-JP (forced)")
+	}
+
+	// A forced encoding still drops the BOM
+	bom, err := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().Bytes([]byte("残高"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded, _, err := Decode(bom, "utf16le"); err != nil || string(decoded) != "残高" {
+		t.Errorf("Decode() = %q, %v, want %q", decoded, err, "残高")
+	}
+
+	if _, _, err := Decode(data, "latin1"); err == nil {
+		t.Error("Decode() expected error for an unknown encoding")
+	}
+}
+
+func TestLookupEncoding(t *testing.T) {
+	tests := map[string]string{
+		"":            "",
+		"cp932":       CP932,
+		"Shift_JIS":   CP932,
+		"Windows-31J": CP932,
+		"sjis":        CP932,
+		"euc-jp":      EUCJP,
+		"UTF-8":       UTF8,
+		"utf8":        UTF8,
+		"utf-16le":    UTF16LE,
+		"UTF16BE":     UTF16BE,
+	}
+	for name, expected := range tests {
+		if got, err := LookupEncoding(name); err != nil || got != expected {
+			t.Errorf("LookupEncoding(%q) = %q, %v, want %q", name, got, err, expected)
+		}
+	}
+}
+
+func TestReadCSVAs(t *testing.T) {
+	records, charset, err := ReadCSVAs(strings.NewReader(string(encode(t, CP932, sampleCSV))), "")
+	if err != nil {
+		t.Fatalf("ReadCSVAs() error = %v", err)
+	}
+	if charset.Name != CP932 {
+		t.Errorf("ReadCSVAs() charset = %q, want %q", charset.Name, CP932)
+	}
+	expected := [][]string{{"ご利用日", "ご利用店名", "金額"}, {"2025/12/23", "㈱テスト①髙島屋", "1234"}}
+	if !reflect.DeepEqual(records, expected) {
+		t.Errorf("ReadCSVAs() = %q, want %q", records, expected)
+	}
+}
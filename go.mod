@@ -4,7 +4,9 @@ go 1.25
 
 require (
 	github.com/fsnotify/fsnotify v1.7.0
+	github.com/ledongthuc/pdf v0.0.0-20250511090121-5959a4027728
 	golang.org/x/text v0.32.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// inputs are the -input directories and the exports to process in them
+type inputs struct {
+	Dirs      []string
+	Recursive bool     // Also look in subdirectories
+	Include   []string // Globs of files to process, all exports when empty
+	Exclude   []string // Globs of files and subdirectories to skip
+	Skip      []string // Paths never processed, with everything under them (the output directory)
+}
+
+// inputList is the value of -input: directories separated by commas, and
+// the flag may be repeated
+type inputList struct {
+	dirs []string
+	set  bool
+}
+
+func (l *inputList) String() string {
+	return strings.Join(l.dirs, ",")
+}
+
+func (l *inputList) Set(value string) error {
+	if !l.set {
+		l.dirs, l.set = nil, true // Replace the default
+	}
+	l.dirs = append(l.dirs, splitList(value)...)
+	return nil
+}
+
+// splitList splits a comma separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var list []string
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			list = append(list, entry)
+		}
+	}
+	return list
+}
+
+// parseGlobs parses the globs of -include or -exclude, e.g. "*.csv,smbc/*"
+func parseGlobs(value string) ([]string, error) {
+	globs := splitList(value)
+	for _, glob := range globs {
+		if _, err := path.Match(glob, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %w", glob, err)
+		}
+	}
+	return globs, nil
+}
+
+// isInputFile reports whether name is a CSV or PDF export, whatever the
+// case of its extension (some banks export .CSV)
+func isInputFile(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	return ext == ".csv" || ext == ".pdf"
+}
+
+func isPDF(name string) bool {
+	return strings.EqualFold(path.Ext(name), ".pdf")
+}
+
+// matchGlob matches a glob without a slash against the name of rel and
+// other globs against the whole of rel, ignoring case
+func matchGlob(glob, rel string) bool {
+	if !strings.Contains(glob, "/") {
+		rel = path.Base(rel)
+	}
+	matched, _ := path.Match(strings.ToLower(glob), strings.ToLower(rel))
+	return matched
+}
+
+func matchAny(globs []string, rel string) bool {
+	for _, glob := range globs {
+		if matchGlob(glob, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// rel is filePath relative to the input directory it is in, with slashes
+func (in inputs) rel(filePath string) (string, bool) {
+	for _, dir := range in.Dirs {
+		rel, err := filepath.Rel(dir, filePath)
+		if err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return filepath.ToSlash(rel), true
+		}
+	}
+	return "", false
+}
+
+// skipped reports whether filePath is one of the Skip paths or under one
+func (in inputs) skipped(filePath string) bool {
+	abs, err := filepath.Abs(filePath)
+	if err != nil {
+		return false
+	}
+	for _, skip := range in.Skip {
+		skipAbs, err := filepath.Abs(skip)
+		if err != nil {
+			continue
+		}
+		if rel, err := filepath.Rel(skipAbs, abs); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsFile reports whether filePath is an export to process
+func (in inputs) acceptsFile(filePath string) bool {
+	rel, ok := in.rel(filePath)
+	if !ok || !isInputFile(rel) || isTempDownload(rel) || in.skipped(filePath) {
+		return false
+	}
+	if !in.Recursive && strings.Contains(rel, "/") {
+		return false
+	}
+	if len(in.Include) > 0 && !matchAny(in.Include, rel) {
+		return false
+	}
+	return !matchAny(in.Exclude, rel)
+}
+
+// acceptsDir reports whether to look into the subdirectory dir
+func (in inputs) acceptsDir(dir string) bool {
+	rel, ok := in.rel(dir)
+	return ok && in.Recursive && !matchAny(in.Exclude, rel) && !in.skipped(dir)
+}
+
+// walk calls visitDir for every directory to look into, the input
+// directories included, and visitFile for every export under root
+func (in inputs) walk(root string, visitDir, visitFile func(string)) error {
+	return filepath.WalkDir(root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if filePath != root && !in.acceptsDir(filePath) {
+				return filepath.SkipDir
+			}
+			visitDir(filePath)
+			return nil
+		}
+		if in.acceptsFile(filePath) {
+			visitFile(filePath)
+		}
+		return nil
+	})
+}
+
+// files lists the exports in all input directories
+func (in inputs) files() ([]string, error) {
+	var files []string
+	for _, dir := range in.Dirs {
+		err := in.walk(dir, func(string) {}, func(filePath string) {
+			files = append(files, filePath)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read input directory %q: %w", dir, err)
+		}
+	}
+	return files, nil
+}
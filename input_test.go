@@ -0,0 +1,129 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// inputTree creates files (slash separated, relative to a new directory)
+// and returns the directory
+func inputTree(t *testing.T, files ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range files {
+		filePath := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		writeFile(t, filePath, "content")
+	}
+	return dir
+}
+
+func TestInputs_Files(t *testing.T) {
+	dir := inputTree(t,
+		"smbc.csv",
+		"RAKUTEN.CSV",
+		"statement.Pdf",
+		"notes.txt",
+		"epos.csv.crdownload",
+		"smbc/2025-12.csv",
+		"smbc/archive/2024-12.csv",
+		"dropbox/view.csv",
+	)
+	tests := []struct {
+		name string
+		in   inputs
+		want []string
+	}{
+		{"top level", inputs{}, []string{"RAKUTEN.CSV", "smbc.csv", "statement.Pdf"}},
+		{"recursive", inputs{Recursive: true}, []string{"RAKUTEN.CSV", "dropbox/view.csv", "smbc/2025-12.csv", "smbc/archive/2024-12.csv", "smbc.csv", "statement.Pdf"}},
+		{"include name", inputs{Recursive: true, Include: []string{"*.csv"}}, []string{"RAKUTEN.CSV", "dropbox/view.csv", "smbc/2025-12.csv", "smbc/archive/2024-12.csv", "smbc.csv"}},
+		{"include path", inputs{Recursive: true, Include: []string{"smbc/*"}}, []string{"smbc/2025-12.csv"}},
+		{"exclude directory", inputs{Recursive: true, Exclude: []string{"archive", "dropbox"}}, []string{"RAKUTEN.CSV", "smbc/2025-12.csv", "smbc.csv", "statement.Pdf"}},
+		{"exclude file", inputs{Exclude: []string{"rakuten.*"}}, []string{"smbc.csv", "statement.Pdf"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.in.Dirs = []string{dir}
+			files, err := tt.in.files()
+			if err != nil {
+				t.Fatalf("files() error = %v", err)
+			}
+			var got []string
+			for _, filePath := range files {
+				rel, _ := filepath.Rel(dir, filePath)
+				got = append(got, filepath.ToSlash(rel))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("files() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInputs_Files_MultipleDirs(t *testing.T) {
+	downloads := inputTree(t, "smbc.csv")
+	dropbox := inputTree(t, "view.csv")
+
+	files, err := inputs{Dirs: []string{downloads, dropbox}}.files()
+	if err != nil {
+		t.Fatalf("files() error = %v", err)
+	}
+	want := []string{filepath.Join(downloads, "smbc.csv"), filepath.Join(dropbox, "view.csv")}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("files() = %v, want %v", files, want)
+	}
+}
+
+func TestInputs_Files_SkipsOutput(t *testing.T) {
+	dir := inputTree(t,
+		"smbc.csv",
+		"ynab_output/20260101_090000/smbc_smbc.csv",
+		"ynab_output/20260101_090000/suica_statement.pdf",
+		"ynab_output/ynab_import_ledger.jsonl",
+		"ynab_output_old/view.csv",
+	)
+	// A relative output directory must be recognised inside an absolute input directory
+	t.Chdir(dir)
+	in := inputs{Dirs: []string{dir}, Recursive: true, Skip: []string{"ynab_output", filepath.Join("ynab_output", "ynab_import_ledger.jsonl")}}
+
+	files, err := in.files()
+	if err != nil {
+		t.Fatalf("files() error = %v", err)
+	}
+	want := []string{filepath.Join(dir, "smbc.csv"), filepath.Join(dir, "ynab_output_old", "view.csv")}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("files() = %v, want %v", files, want)
+	}
+	// Watching skips events in the output directory too
+	if in.acceptsFile(filepath.Join(dir, "ynab_output", "20260101_090000", "smbc_smbc.csv")) || in.acceptsDir(filepath.Join(dir, "ynab_output")) {
+		t.Error("output directory accepted")
+	}
+}
+
+func TestInputList_Set(t *testing.T) {
+	list := &inputList{dirs: []string{"~/Downloads"}}
+	for _, value := range []string{"~/Downloads, ~/Dropbox/banks", "/srv/exports"} {
+		if err := list.Set(value); err != nil {
+			t.Fatalf("Set(%q) error = %v", value, err)
+		}
+	}
+	// The default is replaced, not added to
+	if want := []string{"~/Downloads", "~/Dropbox/banks", "/srv/exports"}; !reflect.DeepEqual(list.dirs, want) {
+		t.Errorf("dirs = %v, want %v", list.dirs, want)
+	}
+}
+
+func TestParseGlobs(t *testing.T) {
+	globs, err := parseGlobs(" *.csv , smbc/* ,")
+	if err != nil || !reflect.DeepEqual(globs, []string{"*.csv", "smbc/*"}) {
+		t.Errorf("parseGlobs() = %v, %v", globs, err)
+	}
+	if _, err := parseGlobs("[a-"); err == nil {
+		t.Error("parseGlobs() expected error for a malformed glob")
+	}
+}
@@ -5,61 +5,30 @@ import (
 	"fmt"
 	"os"
 	"path"
-	"strconv"
-	"strings"
 	"time"
 
-	"github.com/fsnotify/fsnotify"
+	"cppcho.com/ynab_import/encoding"
+	"cppcho.com/ynab_import/parsers"
+	"cppcho.com/ynab_import/pdftext"
+	"cppcho.com/ynab_import/sink"
 )
 
-type YnabRecord struct {
-	date   string
-	payee  string
-	memo   string
-	amount string
-}
-
-type ParseResult struct {
-	ValidRecords []YnabRecord
-	SkippedRows  []SkippedRow
-}
-
-type SkippedRow struct {
-	RowNumber int
-	RawData   []string
-	Reason    string
-}
-
-type Parser interface {
-	Name() string
-	Parse(records [][]string) (*ParseResult, error)
-}
+// Parsers tried in order; definitions from -parsers-config are prepended
+var registry = parsers.Builtin()
 
-var parsers []Parser = []Parser{Smbc{}, Rakuten{}, Epos{}, View{}, Saison{}, RakutenCard{}, Sbi{}, SmbcCard{}, SmbcCard2{}, Shinsei{}, Suica{}, PayPay{}}
+// pdfRegistry is tried in order for PDF files
+var pdfRegistry = parsers.BuiltinPDF()
 
-func flipSign(str string) string {
-	// Remove commas
-	str = strings.Replace(str, ",", "", -1)
+// forcedParser and forcedPDFParser are the parser named by -parser,
+// skipping detection for CSV or PDF files respectively
+var forcedParser parsers.Parser
+var forcedPDFParser parsers.PDFParser
 
-	// Handle empty strings
-	if str == "" {
-		return "0"
-	}
+// pdfExtractor turns PDF statements into text, chosen by -pdf-extractor
+var pdfExtractor = pdftext.Default
 
-	// Try parsing as integer
-	val, err := strconv.Atoi(str)
-	if err != nil {
-		// Try parsing as float and convert to int
-		floatVal, floatErr := strconv.ParseFloat(str, 64)
-		if floatErr != nil {
-			fmt.Fprintf(os.Stderr, "Error: invalid value for flipSign: %q (not a number)\n", str)
-			return "0"
-		}
-		val = int(floatVal)
-	}
-
-	return strconv.Itoa(val * -1)
-}
+// csvEncoding is the encoding set with -encoding, empty to detect it per file
+var csvEncoding string
 
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -84,172 +53,207 @@ func expandHomeDir(path string) string {
 	return homeDir + path[1:]
 }
 
-func processFile(filePath, outputDir string) error {
+func processFile(filePath string, out sink.Sink) error {
 	// Check if this is a PDF file
-	if strings.HasSuffix(filePath, ".pdf") {
-		return processPDFFile(filePath, outputDir)
+	if isPDF(filePath) {
+		return processPDFFile(filePath, out)
 	}
 
 	fmt.Printf("Parsing %v ...", filePath)
 
-	rawRecords, err := readCsvToRawRecords(filePath)
+	f, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read CSV: %w", err)
+	}
+	defer f.Close()
+	csvReader, charset, err := encoding.NewCSVReader(f, csvEncoding)
+	if err != nil {
+		return fmt.Errorf("failed to read CSV: %w", err)
+	}
+	// Only the first rows are held for detection, the rest stream through the parser
+	head, rows, err := parsers.Peek(csvReader, parsers.DetectRows)
 	if err != nil {
 		return fmt.Errorf("failed to read CSV: %w", err)
 	}
 
-	fileName := path.Base(filePath)
-	for _, parser := range parsers {
-		parsed, err := parser.Parse(rawRecords)
-
-		// Error occurred during parsing
-		if err != nil {
-			return fmt.Errorf("parser %s failed: %w", parser.Name(), err)
-		}
-
-		// No match (not this parser's format)
-		if parsed == nil {
-			continue
-		}
-
-		// Match found - write output
-		fmt.Printf(" Matched parser %v\n", parser.Name())
-		dstPath := path.Join(outputDir, parser.Name()+"_"+fileName)
-
-		if err := writeRecordsToCsv(parsed.ValidRecords, dstPath); err != nil {
-			return fmt.Errorf("failed to write output: %w", err)
-		}
-
-		// Display statistics
-		fmt.Printf("Converted %d row(s)", len(parsed.ValidRecords))
-		if len(parsed.SkippedRows) > 0 {
-			fmt.Printf(", skipped %d row(s)", len(parsed.SkippedRows))
-		}
-		fmt.Printf("\n")
-
-		// Display skipped rows with details
-		if len(parsed.SkippedRows) > 0 {
-			for _, skipped := range parsed.SkippedRows {
-				fmt.Fprintf(os.Stderr, "Skipped row %d: %v (reason: %s)\n",
-					skipped.RowNumber, skipped.RawData, skipped.Reason)
-			}
-		}
-
-		fmt.Printf("Wrote to %v\n", dstPath)
-		return nil // Success
+	parser, others, err := selectParser(head)
+	if err != nil {
+		return err
+	}
+	if parser == nil {
+		fmt.Printf(" No matched parser (%s)\n", charset)
+		return nil // Not an error - just no parser matched
 	}
 
-	fmt.Println(" No matched parser")
-	return nil // Not an error - just no parser matched
+	fmt.Printf(" Matched parser %v (%s)\n", parser.Name(), charset)
+	for _, other := range others {
+		warnAlsoMatches(other.Parser.Name(), other.Score)
+	}
+	if streaming, ok := sink.Streaming(out); ok {
+		return streamResult(parser, path.Base(filePath), rows, streaming)
+	}
+	parsed, err := parsers.ParseRows(parser, rows)
+	if err != nil {
+		return err
+	}
+	return writeResult(parser, path.Base(filePath), parsed, out)
 }
 
-func processPDFFile(filePath, outputDir string) error {
-	fmt.Printf("Parsing %v ...", filePath)
-
-	fileName := path.Base(filePath)
-	baseName := strings.TrimSuffix(fileName, ".pdf") + ".csv"
-
-	// Try Suica parser (currently only PDF parser)
-	suicaParser := Suica{}
-	parsed, err := suicaParser.ParsePDF(filePath)
-
-	// Error occurred during parsing
+// streamResult parses rows straight into a sink that writes one record at a
+// time, so only the skipped rows of the file are held
+func streamResult(parser parsers.Parser, fileName string, rows parsers.RowReader, out sink.StreamSink) error {
+	w, err := out.Stream(parser, fileName)
 	if err != nil {
-		return fmt.Errorf("parser %s failed: %w", suicaParser.Name(), err)
+		return fmt.Errorf("failed to write output: %w", err)
 	}
 
-	// No match (not this parser's format)
-	if parsed == nil {
-		fmt.Println(" No matched parser")
+	var balances parsers.BalanceTracker
+	converted := 0
+	skipped, err := parsers.StreamRows(parser, rows, func(record parsers.Transaction) error {
+		balances.Add(record)
+		converted++
+		if err := w.WriteRecord(record); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
 		return nil
+	})
+	if err != nil {
+		w.Abort()
+		return err
 	}
+	dstPath, err := w.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	reportResult(converted, skipped, &balances, dstPath)
+	return nil
+}
 
-	// Match found - write output
-	fmt.Printf(" Matched parser %v\n", suicaParser.Name())
-	dstPath := path.Join(outputDir, suicaParser.Name()+"_"+baseName)
+func warnAlsoMatches(name string, score int) {
+	fmt.Fprintf(os.Stderr, "Warning: %s also matches this file (score %d), use -parser to choose\n", name, score)
+}
 
-	if err := writeRecordsToCsv(parsed.ValidRecords, dstPath); err != nil {
+// writeResult writes a parsed file through out and reports what happened
+func writeResult(parser parsers.Source, fileName string, parsed *parsers.ParseResult, out sink.Sink) error {
+	dstPath, err := out.Write(parser, fileName, parsed)
+	if err != nil {
 		return fmt.Errorf("failed to write output: %w", err)
 	}
 
-	// Display statistics
-	fmt.Printf("Converted %d row(s)", len(parsed.ValidRecords))
-	if len(parsed.SkippedRows) > 0 {
-		fmt.Printf(", skipped %d row(s)", len(parsed.SkippedRows))
+	var balances parsers.BalanceTracker
+	for _, record := range parsed.ValidRecords {
+		balances.Add(record)
+	}
+	reportResult(len(parsed.ValidRecords), parsed.SkippedRows, &balances, dstPath)
+	return nil // Success
+}
+
+// reportResult prints the statistics, skipped rows and balances of a file
+// written to dstPath
+func reportResult(converted int, skipped []parsers.SkippedRow, balances *parsers.BalanceTracker, dstPath string) {
+	fmt.Printf("Converted %d row(s)", converted)
+	if len(skipped) > 0 {
+		fmt.Printf(", skipped %d row(s)", len(skipped))
 	}
 	fmt.Printf("\n")
 
 	// Display skipped rows with details
-	if len(parsed.SkippedRows) > 0 {
-		for _, skipped := range parsed.SkippedRows {
-			fmt.Fprintf(os.Stderr, "Skipped row %d: %v (reason: %s)\n",
-				skipped.RowNumber, skipped.RawData, skipped.Reason)
-		}
+	for _, row := range skipped {
+		fmt.Fprintf(os.Stderr, "Skipped row %d: %v (reason: %s)\n",
+			row.RowNumber, row.RawData, row.Reason)
 	}
+	printBalances(balances)
 
 	fmt.Printf("Wrote to %v\n", dstPath)
-	return nil // Success
 }
 
-func processDirectory(inputDir, outputDir string) error {
-	files, err := os.ReadDir(inputDir)
-	if err != nil {
-		return fmt.Errorf("failed to read input directory %q: %w", inputDir, err)
+// selectParser picks the parser most certain to recognize the rows and
+// returns the other parsers that claim them too. The parser is nil when
+// none matched.
+func selectParser(records [][]string) (parsers.Parser, []parsers.Candidate, error) {
+	if forcedPDFParser != nil {
+		return nil, nil, fmt.Errorf("parser %s only reads PDF files", forcedPDFParser.Name())
 	}
-
-	for _, file := range files {
-		if !file.IsDir() && (strings.HasSuffix(file.Name(), ".csv") || strings.HasSuffix(file.Name(), ".pdf")) {
-			srcPath := path.Join(inputDir, file.Name())
-			if err := processFile(srcPath, outputDir); err != nil {
-				fmt.Fprintf(os.Stderr, "Error processing %s: %v\n", srcPath, err)
-			}
+	if forcedParser != nil {
+		if detection := parsers.SafeDetect(forcedParser, records); detection.Score == parsers.ScoreNone {
+			return nil, nil, fmt.Errorf("parser %s does not recognize the file: %s", forcedParser.Name(), detection.Reason)
 		}
+		return forcedParser, nil, nil
 	}
-	return nil
+
+	candidates := parsers.Rank(registry, records)
+	if len(candidates) == 0 {
+		return nil, nil, nil
+	}
+	return candidates[0].Parser, candidates[1:], nil
 }
 
-func watchMode(inputDir, outputDir string) error {
-	watcher, err := fsnotify.NewWatcher()
+func processPDFFile(filePath string, out sink.Sink) error {
+	fmt.Printf("Parsing %v ...", filePath)
+
+	text, err := pdfExtractor.Extract(filePath)
 	if err != nil {
-		return fmt.Errorf("failed to create watcher: %w", err)
+		return err
 	}
-	defer watcher.Close()
 
-	// Process existing files first
-	fmt.Println("Processing existing files...")
-	if err := processDirectory(inputDir, outputDir); err != nil {
+	parser, others, err := selectPDFParser(text)
+	if err != nil {
 		return err
 	}
-
-	// Start watching
-	err = watcher.Add(inputDir)
+	if parser == nil {
+		fmt.Println(" No matched parser")
+		return nil // Not an error - just no parser matched
+	}
+	fileName := path.Base(filePath)
+	parsed, err := parsers.ParsePDF(parser, fileName, text)
 	if err != nil {
-		return fmt.Errorf("failed to watch directory: %w", err)
+		return err
+	}
+	if parsed == nil {
+		fmt.Println(" No matched parser")
+		return nil
 	}
 
-	fmt.Printf("Watching %s for new or changed CSV files... (Press Ctrl+C to stop)\n", inputDir)
+	fmt.Printf(" Matched parser %v\n", parser.Name())
+	for _, other := range others {
+		warnAlsoMatches(other.Parser.Name(), other.Score)
+	}
+	return writeResult(parser, fileName, parsed, out)
+}
 
-	for {
-		select {
-		case event, ok := <-watcher.Events:
-			if !ok {
-				return nil
-			}
-			// Process on write or create events
-			if event.Op&fsnotify.Write == fsnotify.Write || event.Op&fsnotify.Create == fsnotify.Create {
-				if strings.HasSuffix(event.Name, ".csv") || strings.HasSuffix(event.Name, ".pdf") {
-					fmt.Printf("\nDetected change: %s\n", event.Name)
-					if err := processFile(event.Name, outputDir); err != nil {
-						fmt.Fprintf(os.Stderr, "Error processing %s: %v\n", event.Name, err)
-					}
-				}
-			}
-		case err, ok := <-watcher.Errors:
-			if !ok {
-				return nil
-			}
-			fmt.Fprintf(os.Stderr, "Watcher error: %v\n", err)
+// selectPDFParser is selectParser for the text of a PDF
+func selectPDFParser(text string) (parsers.PDFParser, []parsers.PDFCandidate, error) {
+	if forcedParser != nil {
+		return nil, nil, fmt.Errorf("parser %s only reads CSV files", forcedParser.Name())
+	}
+	if forcedPDFParser != nil {
+		if detection := parsers.SafeDetectPDF(forcedPDFParser, text); detection.Score == parsers.ScoreNone {
+			return nil, nil, fmt.Errorf("parser %s does not recognize the file: %s", forcedPDFParser.Name(), detection.Reason)
 		}
+		return forcedPDFParser, nil, nil
 	}
+
+	candidates := parsers.RankPDF(pdfRegistry, text)
+	if len(candidates) == 0 {
+		return nil, nil, nil
+	}
+	return candidates[0].Parser, candidates[1:], nil
+}
+
+// processInputs processes the exports in the input directories, reporting
+// errors per file
+func processInputs(in inputs, out sink.Sink) error {
+	files, err := in.files()
+	if err != nil {
+		return err
+	}
+
+	for _, srcPath := range files {
+		if err := processFile(srcPath, out); err != nil {
+			fmt.Fprintf(os.Stderr, "Error processing %s: %v\n", srcPath, err)
+		}
+	}
+	return nil
 }
 
 func main() {
@@ -261,48 +265,233 @@ func main() {
 
 func run() error {
 	// Define CLI flags with environment variable defaults
-	inputDir := flag.String("input", getEnvOrDefault("CSV_DIR_IN", "~/Downloads"), "Input directory containing CSV files (env: CSV_DIR_IN, default: ~/Downloads)")
+	inputDirs := &inputList{dirs: splitList(getEnvOrDefault("CSV_DIR_IN", "~/Downloads"))}
+	flag.Var(inputDirs, "input", "Input directories containing CSV and PDF files, separated by commas or with the flag repeated (env: CSV_DIR_IN, default: ~/Downloads)")
+	recursive := flag.Bool("recursive", false, "Also process files in subdirectories of the input directories, and watch new subdirectories")
+	include := flag.String("include", getEnvOrDefault("INPUT_INCLUDE", ""), "Only process files matching one of these globs, e.g. \"*.csv,smbc/*\" (env: INPUT_INCLUDE)")
+	exclude := flag.String("exclude", getEnvOrDefault("INPUT_EXCLUDE", ""), "Skip files and subdirectories matching one of these globs, e.g. \"archive,*_old.csv\" (env: INPUT_EXCLUDE)")
 	outputDir := flag.String("output", getEnvOrDefault("CSV_DIR", "~/Desktop"), "Output directory for converted CSV files (env: CSV_DIR, default: ~/Desktop)")
 	watch := flag.Bool("w", false, "Watch mode: continuously monitor input directory for new or changed CSV files")
 	flag.BoolVar(watch, "watch", false, "Watch mode: continuously monitor input directory for new or changed CSV files")
+	upload := flag.Bool("upload", false, "Upload transactions to the YNAB API instead of writing CSV files")
+	ynabToken := flag.String("ynab-token", getEnvOrDefault("YNAB_TOKEN", ""), "YNAB personal access token (env: YNAB_TOKEN)")
+	ynabBudget := flag.String("ynab-budget", getEnvOrDefault("YNAB_BUDGET_ID", "last-used"), "YNAB budget ID (env: YNAB_BUDGET_ID, default: last-used)")
+	ynabURL := flag.String("ynab-url", getEnvOrDefault("YNAB_API_URL", sink.DefaultYnabBaseURL), "YNAB API base URL (env: YNAB_API_URL)")
+	ynabAccounts := flag.String("ynab-accounts", getEnvOrDefault("YNAB_ACCOUNTS", ""), "Parser to YNAB account mapping, e.g. smbc=ID,rakuten=ID (env: YNAB_ACCOUNTS)")
+	sinceLast := flag.Bool("since-last", false, "Only write transactions that were not exported by a previous -since-last run, recording them in the ledger")
+	format := flag.String("format", "csv", "Output format: csv, ofx (OFX 2.2), ofx1 (OFX 1.0.2), qif, hledger or beancount")
+	accountIDs := flag.String("accounts", getEnvOrDefault("ACCOUNTS", ""), "Parser to account mapping for statement formats, e.g. smbc=1234567 or smbc=Assets:Bank:SMBC (env: ACCOUNTS)")
+	splitAccounts := flag.String("split-accounts", getEnvOrDefault("SPLIT_ACCOUNTS", ""), "Write the records of one card holder or family member as a separate account, e.g. smbc_card:family=smbc_card_family (env: SPLIT_ACCOUNTS)")
+	installments := flag.String("installments", getEnvOrDefault("INSTALLMENTS", ""), "Show card payment plans: memo (plan and count in the memo) or schedule (one transaction per installment payment) (env: INSTALLMENTS)")
+	normalize := flag.String("normalize", getEnvOrDefault("NORMALIZE", parsers.NormalizeOff), "Payee and memo normalization: full, half or keep (katakana width after NFKC) or off, per parser as parser=policy, e.g. full,shinsei=half (env: NORMALIZE, default: off)")
+	fxColumns := flag.Bool("fx-columns", false, "Write foreign currency amount, currency and FX rate as extra CSV columns instead of in the memo")
+	beancountOpen := flag.Bool("beancount-open", false, "Start each beancount file with open directives for its accounts, for files loaded on their own")
+	rulesFile := flag.String("rules", getEnvOrDefault("RULES_FILE", ""), "YAML file with payee rewrite and categorization rules (env: RULES_FILE)")
+	dryRun := flag.Bool("dry-run", false, "Show which rule fires for each transaction without writing anything")
+	parserName := flag.String("parser", "", "Use this parser for every input file instead of detecting one")
+	parsersConfig := flag.String("parsers-config", getEnvOrDefault("PARSERS_CONFIG", ""), "YAML file with additional parser definitions (env: PARSERS_CONFIG)")
+	suicaChargePayee := flag.String("suica-charge-payee", getEnvOrDefault("SUICA_CHARGE_PAYEE", parsers.DefaultSuicaChargePayee), "Payee of Suica auto-charges and card charges, e.g. \"Transfer : View Card\" (env: SUICA_CHARGE_PAYEE)")
+	suicaCashPayee := flag.String("suica-cash-payee", getEnvOrDefault("SUICA_CASH_PAYEE", parsers.DefaultSuicaCashChargePayee), "Payee of Suica cash charges (env: SUICA_CASH_PAYEE)")
+	encodingName := flag.String("encoding", getEnvOrDefault("CSV_ENCODING", ""), "Encoding of CSV files: UTF-8, CP932, EUC-JP, UTF-16LE or UTF-16BE (env: CSV_ENCODING, default: detect per file)")
+	watchQuiet := flag.String("watch-quiet", getEnvOrDefault("WATCH_QUIET", defaultWatchQuiet.String()), "Watch mode: parse a file once it had no changes for this long, e.g. 500ms or 5s (env: WATCH_QUIET)")
+	pdfExtractorName := flag.String("pdf-extractor", getEnvOrDefault("PDF_EXTRACTOR", pdftext.Default.Name()), "PDF text extraction backend: native or pdftotext (env: PDF_EXTRACTOR)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [command]\n\nCommands:\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "  detect <file>           Explain which parsers match a file and why the others don't\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  definitions             Print the built-in parsers as a -parsers-config file to adapt\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  reset-ledger <account>  Forget exported transactions of one account (parser name)\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
 	// Expand ~ in paths
-	*inputDir = expandHomeDir(*inputDir)
+	in := inputs{Recursive: *recursive}
+	for _, dir := range inputDirs.dirs {
+		in.Dirs = append(in.Dirs, expandHomeDir(dir))
+	}
 	*outputDir = expandHomeDir(*outputDir)
 
-	// Create output dir (e.g. ~/Desktop/20060102_output)
-	now := time.Now().UTC().Format("20060102")
-	timestampedOutputDir := path.Join(*outputDir, now+"_output")
-	if err := os.MkdirAll(timestampedOutputDir, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory %q: %w", timestampedOutputDir, err)
+	// Definitions take precedence so they can override a built-in whose layout changed
+	if *parsersConfig != "" {
+		defined, err := parsers.LoadDefinitions(expandHomeDir(*parsersConfig))
+		if err != nil {
+			return fmt.Errorf("failed to load parser definitions: %w", err)
+		}
+		registry = append(defined, registry...)
+	}
+
+	for i, parser := range pdfRegistry {
+		if _, ok := parser.(parsers.Suica); ok {
+			pdfRegistry[i] = parsers.Suica{ChargePayee: *suicaChargePayee, CashChargePayee: *suicaCashPayee}
+		}
+	}
+
+	if *parserName != "" {
+		for _, parser := range registry {
+			if parser.Name() == *parserName {
+				forcedParser = parser
+				break
+			}
+		}
+		for _, parser := range pdfRegistry {
+			if forcedParser == nil && parser.Name() == *parserName {
+				forcedPDFParser = parser
+				break
+			}
+		}
+		if forcedParser == nil && forcedPDFParser == nil {
+			return fmt.Errorf("unknown parser %q", *parserName)
+		}
+	}
+
+	extractor, err := pdftext.ByName(*pdfExtractorName)
+	if err != nil {
+		return err
+	}
+	pdfExtractor = extractor
+
+	if csvEncoding, err = encoding.LookupEncoding(*encodingName); err != nil {
+		return err
+	}
+	if in.Include, err = parseGlobs(*include); err != nil {
+		return fmt.Errorf("invalid -include: %w", err)
+	}
+	if in.Exclude, err = parseGlobs(*exclude); err != nil {
+		return fmt.Errorf("invalid -exclude: %w", err)
+	}
+
+	// The ledger lives in the base output dir so it spans the dated folders
+	ledgerPath := path.Join(*outputDir, sink.LedgerFileName)
+	// Never read back our own output when it is inside an input directory
+	in.Skip = []string{ledgerPath}
+
+	if flag.NArg() > 0 {
+		switch flag.Arg(0) {
+		case "detect":
+			if flag.NArg() != 2 {
+				return fmt.Errorf("usage: detect <file>")
+			}
+			return explainDetection(os.Stdout, expandHomeDir(flag.Arg(1)))
+		case "definitions":
+			_, err := os.Stdout.Write(parsers.BuiltinDefinitionsYAML())
+			return err
+		case "reset-ledger":
+			if flag.NArg() != 2 {
+				return fmt.Errorf("usage: reset-ledger <account>")
+			}
+			return resetLedger(ledgerPath, flag.Arg(1))
+		default:
+			return fmt.Errorf("unknown command %q", flag.Arg(0))
+		}
+	}
+
+	var out sink.Sink
+	if *upload {
+		if *ynabToken == "" {
+			return fmt.Errorf("-upload requires a YNAB token (-ynab-token or YNAB_TOKEN)")
+		}
+		accounts, err := sink.ParseAccountMap(*ynabAccounts)
+		if err != nil {
+			return err
+		}
+		out = sink.YnabSink{
+			BaseURL:    *ynabURL,
+			Token:      *ynabToken,
+			BudgetID:   *ynabBudget,
+			AccountIDs: accounts,
+		}
+	} else {
+		// Create output dir (e.g. ~/Desktop/20060102_output); a dry run writes nothing
+		now := time.Now().UTC().Format("20060102")
+		timestampedOutputDir := path.Join(*outputDir, now+"_output")
+		in.Skip = append(in.Skip, timestampedOutputDir)
+		if !*dryRun {
+			if err := os.MkdirAll(timestampedOutputDir, 0755); err != nil {
+				return fmt.Errorf("failed to create output directory %q: %w", timestampedOutputDir, err)
+			}
+		}
+		accounts, err := sink.ParseAccountMap(*accountIDs)
+		if err != nil {
+			return err
+		}
+		out, err = sink.NewFileSink(*format, timestampedOutputDir, *fxColumns, *beancountOpen, accounts)
+		if err != nil {
+			return err
+		}
 	}
 
+	// The ledger is only kept for -since-last
+	if *sinceLast && !*dryRun {
+		if err := os.MkdirAll(*outputDir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory %q: %w", *outputDir, err)
+		}
+		ledger, err := sink.OpenLedger(ledgerPath)
+		if err != nil {
+			return fmt.Errorf("failed to open ledger: %w", err)
+		}
+		out = sink.LedgerSink{Sink: out, Ledger: ledger}
+	}
+
+	split, err := sink.ParseAccountMap(*splitAccounts)
+	if err != nil {
+		return err
+	}
+	if len(split) > 0 {
+		out = sink.SplitSink{Sink: out, Accounts: split}
+	}
+
+	installmentMode, err := sink.ParseInstallmentMode(*installments)
+	if err != nil {
+		return err
+	}
+	if installmentMode != "" {
+		out = sink.InstallmentSink{Sink: out, Mode: installmentMode}
+	}
+
+	// Rules run first so the ledger and outputs see rewritten records
+	if *rulesFile != "" || *dryRun {
+		var rules []sink.Rule
+		if *rulesFile != "" {
+			rules, err = sink.LoadRules(expandHomeDir(*rulesFile))
+			if err != nil {
+				return fmt.Errorf("failed to load rules: %w", err)
+			}
+		}
+		out = sink.RulesSink{Sink: out, Rules: rules, DryRun: *dryRun}
+	}
+
+	// Normalized before anything else so rules match one spelling of a payee
+	defaultNormalization, normalizations, err := sink.ParseNormalizePolicies(*normalize)
+	if err != nil {
+		return err
+	}
+	out = sink.NormalizeSink{Sink: out, Default: defaultNormalization, Policies: normalizations}
+
 	if *watch {
 		// Watch mode
-		return watchMode(*inputDir, timestampedOutputDir)
+		quiet, err := time.ParseDuration(*watchQuiet)
+		if err != nil {
+			return fmt.Errorf("invalid -watch-quiet %q: %w", *watchQuiet, err)
+		}
+		return watchMode(in, quiet, out)
 	}
 
 	// One-time processing mode
-	files, err := os.ReadDir(*inputDir)
+	files, err := in.files()
 	if err != nil {
-		return fmt.Errorf("failed to read input directory %q: %w", *inputDir, err)
+		return err
 	}
 
 	// Track errors but continue processing
 	var errors []error
 	successCount := 0
 
-	for _, file := range files {
-		if !file.IsDir() && (strings.HasSuffix(file.Name(), ".csv") || strings.HasSuffix(file.Name(), ".pdf")) {
-			srcPath := path.Join(*inputDir, file.Name())
-
-			if err := processFile(srcPath, timestampedOutputDir); err != nil {
-				fmt.Printf(" ERROR: %v\n", err)
-				errors = append(errors, fmt.Errorf("%s: %w", file.Name(), err))
-			} else {
-				successCount++
-			}
+	for _, srcPath := range files {
+		if err := processFile(srcPath, out); err != nil {
+			fmt.Printf(" ERROR: %v\n", err)
+			errors = append(errors, fmt.Errorf("%s: %w", srcPath, err))
+		} else {
+			successCount++
 		}
 	}
 
@@ -318,11 +507,26 @@ func run() error {
 	return nil
 }
 
-// 2006-01-02T15:04:05
-func convertDate(fromLayout, toLayout, value string) (string, error) {
-	date, err := time.Parse(fromLayout, value)
+func resetLedger(ledgerPath, account string) error {
+	ledger, err := sink.OpenLedger(ledgerPath)
+	if err != nil {
+		return fmt.Errorf("failed to open ledger: %w", err)
+	}
+	removed, err := ledger.Reset(account)
 	if err != nil {
-		return "", err
+		return fmt.Errorf("failed to reset ledger: %w", err)
+	}
+	fmt.Printf("Removed %d ledger entries for %s\n", removed, account)
+	return nil
+}
+
+// printBalances reports balance breaks and the closing balance of a statement
+func printBalances(balances *parsers.BalanceTracker) {
+	for _, b := range balances.Breaks() {
+		fmt.Fprintf(os.Stderr, "Balance break on %s (%s, %s): expected %s after %s, got %s\n",
+			b.Record.Date, b.Record.Description(), b.Record.Amount, b.Expected, b.Previous, b.Record.Balance)
+	}
+	if balance, date, ok := balances.Closing(); ok {
+		fmt.Printf("Closing balance: %s %s on %s\n", balance, balance.Currency, date)
 	}
-	return date.Format(toLayout), nil
 }
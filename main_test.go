@@ -1,10 +1,16 @@
 package main
 
 import (
+	"bytes"
+	"flag"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"cppcho.com/ynab_import/parsers"
+	"cppcho.com/ynab_import/sink"
 )
 
 func TestProcessFile(t *testing.T) {
@@ -19,13 +25,19 @@ func TestProcessFile(t *testing.T) {
 	}{
 		{
 			name:        "valid SMBC CSV",
-			filePath:    "testdata/parsers/smbc_valid.csv",
+			filePath:    "parsers/testdata/smbc_valid.csv",
 			shouldError: false,
 			shouldMatch: true,
 		},
 		{
 			name:        "valid Rakuten CSV",
-			filePath:    "testdata/parsers/rakuten_valid.csv",
+			filePath:    "parsers/testdata/rakuten_valid.csv",
+			shouldError: false,
+			shouldMatch: true,
+		},
+		{
+			name:        "valid Suica PDF",
+			filePath:    "parsers/testdata/JE000000000000000_20251028_20260101110125.pdf",
 			shouldError: false,
 			shouldMatch: true,
 		},
@@ -39,7 +51,7 @@ func TestProcessFile(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := processFile(tt.filePath, outputDir)
+			err := processFile(tt.filePath, sink.CsvSink{OutputDir: outputDir})
 			if tt.shouldError {
 				if err == nil {
 					t.Errorf("processFile(%q) expected error, got nil", tt.filePath)
@@ -83,8 +95,8 @@ func TestProcessDirectory(t *testing.T) {
 		src  string
 		dest string
 	}{
-		{"testdata/parsers/smbc_valid.csv", "smbc_test.csv"},
-		{"testdata/parsers/rakuten_valid.csv", "rakuten_test.csv"},
+		{"parsers/testdata/smbc_valid.csv", "smbc_test.csv"},
+		{"parsers/testdata/rakuten_valid.csv", "rakuten_test.csv"},
 	}
 
 	for _, tf := range testFiles {
@@ -111,9 +123,9 @@ func TestProcessDirectory(t *testing.T) {
 	}
 
 	// Process the directory
-	err := processDirectory(inputDir, outputDir)
+	err := processInputs(inputs{Dirs: []string{inputDir}}, sink.CsvSink{OutputDir: outputDir})
 	if err != nil {
-		t.Errorf("processDirectory() unexpected error: %v", err)
+		t.Errorf("processInputs() unexpected error: %v", err)
 	}
 
 	// Verify that output files were created
@@ -136,8 +148,228 @@ func TestProcessDirectory(t *testing.T) {
 
 func TestProcessDirectoryNonExistent(t *testing.T) {
 	outputDir := t.TempDir()
-	err := processDirectory("/nonexistent/directory", outputDir)
+	err := processInputs(inputs{Dirs: []string{"/nonexistent/directory"}}, sink.CsvSink{OutputDir: outputDir})
 	if err == nil {
-		t.Error("processDirectory() expected error for non-existent directory, got nil")
+		t.Error("processInputs() expected error for non-existent directory, got nil")
+	}
+}
+
+func TestProcessFile_ForcedParser(t *testing.T) {
+	defer func() { forcedParser = nil }()
+	outputDir := t.TempDir()
+
+	// The forced parser is used when it recognizes the file
+	forcedParser = parsers.Smbc{}
+	if err := processFile("parsers/testdata/smbc_valid.csv", sink.CsvSink{OutputDir: outputDir}); err != nil {
+		t.Fatalf("processFile() unexpected error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "smbc_smbc_valid.csv")); err != nil {
+		t.Errorf("expected smbc output: %v", err)
+	}
+
+	// and reports why when it doesn't
+	forcedParser = parsers.Rakuten{}
+	err := processFile("parsers/testdata/smbc_valid.csv", sink.CsvSink{OutputDir: outputDir})
+	if err == nil || !strings.Contains(err.Error(), "header mismatch") {
+		t.Errorf("processFile() error = %v, want header mismatch", err)
+	}
+}
+
+func TestExplainDetection(t *testing.T) {
+	// SMBC export with a renamed column
+	path := filepath.Join(t.TempDir(), "statement.csv")
+	content := "年月日,お引出し,お預入れ金額,お取り扱い内容,残高,メモ,ラベル\n2025/12/26,100,,テスト,900,,\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := explainDetection(&out, path); err != nil {
+		t.Fatalf("explainDetection() error = %v", err)
+	}
+	for _, want := range []string{
+		"Encoding: UTF-8",
+		"Rows: 2",
+		"smbc          rejected: header mismatch in row 1",
+		`column 3: got "お預入れ金額", want "お預入れ"`,
+		"view          rejected: too few rows: want more than 6, got 2",
+		"No parser matched",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("explainDetection() output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := explainDetection(&out, "parsers/testdata/smbc_valid.csv"); err != nil {
+		t.Fatalf("explainDetection() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "smbc          matched (score 100)") || !strings.Contains(out.String(), "smbc would be used") {
+		t.Errorf("explainDetection() output for SMBC export:\n%s", out.String())
+	}
+}
+
+func TestExplainDetection_PDF(t *testing.T) {
+	var out bytes.Buffer
+	if err := explainDetection(&out, "parsers/testdata/JE000000000000000_20251028_20260101110125.pdf"); err != nil {
+		t.Fatalf("explainDetection() error = %v", err)
+	}
+	for _, want := range []string{
+		"Extractor: native",
+		"1: モバイルＳｕｉｃａ　残高ご利用明細",
+		"suica         matched (score 100)",
+		"suica would be used",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("explainDetection() output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestProcessFile_ForcedPDFParser(t *testing.T) {
+	defer func() { forcedPDFParser = nil }()
+	forcedPDFParser = parsers.Suica{}
+
+	err := processFile("parsers/testdata/smbc_valid.csv", sink.CsvSink{OutputDir: t.TempDir()})
+	if err == nil || !strings.Contains(err.Error(), "only reads PDF files") {
+		t.Errorf("processFile() error = %v, want only reads PDF files", err)
+	}
+}
+
+// emptyPDFParser recognizes any PDF but parses nothing out of it
+type emptyPDFParser struct{}
+
+func (emptyPDFParser) Name() string { return "empty" }
+func (emptyPDFParser) Detect(text string) parsers.Detection {
+	return parsers.Detection{Score: parsers.ScoreHeader}
+}
+func (emptyPDFParser) Parse(text string) (*parsers.ParseResult, error) { return nil, nil }
+
+func TestProcessFile_PDFParserWithoutResult(t *testing.T) {
+	defer func() { forcedPDFParser = nil }()
+	forcedPDFParser = emptyPDFParser{}
+
+	outputDir := t.TempDir()
+	if err := processFile("parsers/testdata/JE000000000000000_20251028_20260101110125.pdf", sink.CsvSink{OutputDir: outputDir}); err != nil {
+		t.Fatalf("processFile() unexpected error: %v", err)
+	}
+	if files := listFiles(t, outputDir); len(files) != 0 {
+		t.Errorf("wrote %v, want nothing", files)
+	}
+}
+
+// runWithArgs runs the command line with fresh flags and environment
+func runWithArgs(t *testing.T, args ...string) error {
+	t.Helper()
+	oldArgs, oldFlags := os.Args, flag.CommandLine
+	t.Cleanup(func() { os.Args, flag.CommandLine = oldArgs, oldFlags })
+	os.Args = append([]string{"ynab_import"}, args...)
+	flag.CommandLine = flag.NewFlagSet("ynab_import", flag.ContinueOnError)
+	return run()
+}
+
+// listFiles returns the files under dir, relative and slash separated
+func listFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	var files []string
+	err := filepath.WalkDir(dir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() {
+			rel, _ := filepath.Rel(dir, filePath)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestRun_LedgerAndDryRun(t *testing.T) {
+	inputDir := t.TempDir()
+	data, err := os.ReadFile("parsers/testdata/smbc_valid.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(inputDir, "smbc.csv"), string(data))
+
+	tests := []struct {
+		name       string
+		flags      []string
+		wantFiles  int
+		wantLedger bool
+	}{
+		{"dry run", []string{"-dry-run", "-since-last"}, 0, false},
+		{"no ledger by default", nil, 1, false},
+		{"since last", []string{"-since-last"}, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputDir := filepath.Join(t.TempDir(), "out")
+			if err := runWithArgs(t, append([]string{"-input", inputDir, "-output", outputDir}, tt.flags...)...); err != nil {
+				t.Fatalf("run() error = %v", err)
+			}
+
+			files := listFiles(t, filepath.Dir(outputDir))
+			ledger := false
+			var outputs []string
+			for _, file := range files {
+				if file == "out/"+sink.LedgerFileName {
+					ledger = true
+				} else {
+					outputs = append(outputs, file)
+				}
+			}
+			if ledger != tt.wantLedger || len(outputs) != tt.wantFiles {
+				t.Errorf("wrote %v, want %d output file(s) and ledger %v", files, tt.wantFiles, tt.wantLedger)
+			}
+			if _, err := os.Stat(outputDir); tt.wantFiles == 0 && !os.IsNotExist(err) {
+				t.Errorf("created %s, want no directories either", outputDir)
+			}
+		})
+	}
+}
+
+func TestRun_OutputInInputDirectory(t *testing.T) {
+	data, err := os.ReadFile("parsers/testdata/smbc_valid.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		output func(inputDir string) string
+	}{
+		{"same directory", func(inputDir string) string { return inputDir }},
+		{"parent directory", filepath.Dir},
+		{"subdirectory", func(inputDir string) string { return filepath.Join(inputDir, "out") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputDir := filepath.Join(t.TempDir(), "in")
+			if err := os.MkdirAll(inputDir, 0755); err != nil {
+				t.Fatal(err)
+			}
+			writeFile(t, filepath.Join(inputDir, "smbc.csv"), string(data))
+			outputDir := tt.output(inputDir)
+
+			// The second run must neither drop the input nor read back the first run's output
+			for range 2 {
+				if err := runWithArgs(t, "-recursive", "-input", inputDir, "-output", outputDir); err != nil {
+					t.Fatalf("run() error = %v", err)
+				}
+			}
+			var outputs []string
+			for _, file := range listFiles(t, outputDir) {
+				if strings.HasSuffix(file, "_output/smbc_smbc.csv") {
+					outputs = append(outputs, file)
+				} else if file != "smbc.csv" && file != "in/smbc.csv" {
+					t.Errorf("unexpected file %s", file)
+				}
+			}
+			if len(outputs) != 1 {
+				t.Errorf("outputs = %v, want one smbc_smbc.csv", outputs)
+			}
+		})
 	}
 }
@@ -0,0 +1,116 @@
+package parsers
+
+// BalanceBreak is a record whose running balance does not follow from the
+// previous balance and its amount, usually a missing or reordered row
+type BalanceBreak struct {
+	Record   Transaction
+	Previous Money // Balance of the preceding transaction
+	Expected Money // Previous + amount
+}
+
+// ReconcileBalances checks that every balance equals the previous balance
+// plus the amount. Records are walked oldest first; records without a
+// balance are ignored.
+func ReconcileBalances(records []Transaction) []BalanceBreak {
+	var tracker BalanceTracker
+	for _, record := range records {
+		tracker.Add(record)
+	}
+	return tracker.Breaks()
+}
+
+// ClosingBalance returns the running balance after the latest transaction.
+// Exports are either oldest-first or newest-first, so the end of the
+// statement is whichever end has the later date.
+func ClosingBalance(records []Transaction) (Money, string, bool) {
+	var tracker BalanceTracker
+	for _, record := range records {
+		tracker.Add(record)
+	}
+	return tracker.Closing()
+}
+
+// BalanceTracker is ReconcileBalances and ClosingBalance for records that
+// arrive one at a time in file order, holding only the breaks. Both orders
+// are checked as the records arrive, since the order of the export is only
+// known from its last date.
+type BalanceTracker struct {
+	firstDate, lastDate       string      // Of all dated records
+	firstBalance, lastBalance Transaction // Dated records with a balance
+	balances                  int
+	oldestFirst, newestFirst  []BalanceBreak
+}
+
+// Add adds the next record of the file
+func (t *BalanceTracker) Add(record Transaction) {
+	if record.Date == "" {
+		return
+	}
+	if t.firstDate == "" {
+		t.firstDate = record.Date
+	}
+	t.lastDate = record.Date
+	if !record.HasBalance() {
+		return
+	}
+
+	if t.balances == 0 {
+		t.firstBalance = record
+	} else {
+		previous := t.lastBalance
+		if expected := balanceAfter(previous, record); expected != record.Balance {
+			t.oldestFirst = append(t.oldestFirst, BalanceBreak{Record: record, Previous: previous.Balance, Expected: expected})
+		}
+		// Newest first, the previous row in the file is the next transaction
+		if expected := balanceAfter(record, previous); expected != previous.Balance {
+			t.newestFirst = append(t.newestFirst, BalanceBreak{Record: previous, Previous: record.Balance, Expected: expected})
+		}
+	}
+	t.lastBalance = record
+	t.balances++
+}
+
+func balanceAfter(previous, current Transaction) Money {
+	return Money{
+		Milliunits: previous.Balance.Milliunits + current.Amount.Milliunits,
+		Currency:   previous.Balance.Currency,
+	}
+}
+
+// Breaks returns the balance breaks oldest first, whichever order the export
+// is in
+func (t *BalanceTracker) Breaks() []BalanceBreak {
+	if t.balances < 2 {
+		return nil
+	}
+
+	// Walked from the oldest record, the reverse of the file
+	newestFirst := make([]BalanceBreak, len(t.newestFirst))
+	for i, b := range t.newestFirst {
+		newestFirst[len(newestFirst)-1-i] = b
+	}
+
+	first, last := t.firstBalance.Date, t.lastBalance.Date
+	switch {
+	case first < last:
+		return t.oldestFirst
+	case first > last:
+		return newestFirst
+	}
+	// Single-day statement: trust whichever order reconciles better
+	if len(newestFirst) < len(t.oldestFirst) {
+		return newestFirst
+	}
+	return t.oldestFirst
+}
+
+// Closing returns the running balance after the latest transaction
+func (t *BalanceTracker) Closing() (Money, string, bool) {
+	if t.balances == 0 {
+		return Money{}, "", false
+	}
+	if t.firstDate > t.lastDate {
+		return t.firstBalance.Balance, t.firstBalance.Date, true
+	}
+	return t.lastBalance.Balance, t.lastBalance.Date, true
+}
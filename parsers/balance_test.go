@@ -0,0 +1,91 @@
+package parsers
+
+import (
+	"testing"
+)
+
+func TestReconcileBalances_Fixtures(t *testing.T) {
+	// The SMBC sample's balances don't add up: 4231616 - 23000 is not 4226616
+	expectedBreaks := map[string]int{"smbc": 1}
+
+	for _, fixture := range fixtureParsers {
+		t.Run(fixture.parser.Name(), func(t *testing.T) {
+			result := parseFixture(t, fixture.parser, fixture.file)
+			breaks := ReconcileBalances(result.ValidRecords)
+			if len(breaks) != expectedBreaks[fixture.parser.Name()] {
+				t.Errorf("ReconcileBalances() got %d break(s), want %d", len(breaks), expectedBreaks[fixture.parser.Name()])
+			}
+		})
+	}
+}
+
+func TestReconcileBalances(t *testing.T) {
+	tests := []struct {
+		name     string
+		records  []Transaction
+		expected []string // Dates of the reported breaks
+	}{
+		{
+			name: "oldest first",
+			records: []Transaction{
+				{Date: "2025-12-01", Amount: Yen(-100), Balance: Yen(900)},
+				{Date: "2025-12-02", Amount: Yen(500), Balance: Yen(1400)},
+				{Date: "2025-12-03", Amount: Yen(-400), Balance: Yen(1000)},
+			},
+		},
+		{
+			name: "newest first",
+			records: []Transaction{
+				{Date: "2025-12-03", Amount: Yen(-400), Balance: Yen(1000)},
+				{Date: "2025-12-02", Amount: Yen(500), Balance: Yen(1400)},
+				{Date: "2025-12-01", Amount: Yen(-100), Balance: Yen(900)},
+			},
+		},
+		{
+			name: "missing row",
+			records: []Transaction{
+				{Date: "2025-12-01", Amount: Yen(-100), Balance: Yen(900)},
+				{Date: "2025-12-03", Amount: Yen(-400), Balance: Yen(1000)},
+			},
+			expected: []string{"2025-12-03"},
+		},
+		{
+			name: "missing rows newest first",
+			records: []Transaction{
+				{Date: "2025-12-05", Amount: Yen(-100), Balance: Yen(600)},
+				{Date: "2025-12-03", Amount: Yen(-100), Balance: Yen(800)},
+				{Date: "2025-12-01", Amount: Yen(-100), Balance: Yen(1000)},
+			},
+			expected: []string{"2025-12-03", "2025-12-05"},
+		},
+		{
+			name: "same day newest first",
+			records: []Transaction{
+				{Date: "2025-12-01", Amount: Yen(-3), Balance: Yen(97)},
+				{Date: "2025-12-01", Amount: Yen(-10), Balance: Yen(100)},
+				{Date: "2025-12-01", Amount: Yen(10), Balance: Yen(110)},
+			},
+		},
+		{
+			name: "records without balance are ignored",
+			records: []Transaction{
+				{Date: "2025-12-01", Amount: Yen(-100)},
+				{Date: "2025-12-02", Amount: Yen(500)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breaks := ReconcileBalances(tt.records)
+			if len(breaks) != len(tt.expected) {
+				t.Fatalf("ReconcileBalances() got %d break(s), want %d", len(breaks), len(tt.expected))
+			}
+			for i, date := range tt.expected {
+				if breaks[i].Record.Date != date {
+					t.Errorf("Break[%d] date = %q, want %q", i, breaks[i].Record.Date, date)
+				}
+			}
+		})
+	}
+}
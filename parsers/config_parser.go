@@ -0,0 +1,291 @@
+package parsers
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParserConfig is the top level of a parser definitions file
+type ParserConfig struct {
+	Parsers []ParserDefinition `yaml:"parsers"`
+}
+
+// ParserDefinition describes a CSV layout that needs no Go code: how to
+// recognise the file, where the transactions start and which column holds what
+type ParserDefinition struct {
+	Name string `yaml:"name"`
+
+	// Header is the exact expected header row at HeaderRow
+	Header    []string `yaml:"header"`
+	HeaderRow int      `yaml:"header_row"`
+	// Match lists single cells to check instead of (or in addition to) Header
+	Match []CellMatch `yaml:"match"`
+	// HeaderColumns is the exact number of columns of the header row (0 = any)
+	HeaderColumns int `yaml:"header_columns"`
+
+	// SkipRows is the number of rows before the first transaction
+	SkipRows   int    `yaml:"skip_rows"`
+	DateLayout string `yaml:"date_layout"`
+
+	Columns ColumnMapping `yaml:"columns"`
+
+	// Required lists columns that must be non-empty, otherwise the row is skipped
+	Required []int `yaml:"required"`
+
+	// Currency of the amounts (default JPY)
+	Currency string `yaml:"currency"`
+
+	// AccountType is "bank" (default) or "credit_card"
+	AccountType string `yaml:"account_type"`
+}
+
+// CellMatch checks records[Row][Column] == Value
+type CellMatch struct {
+	Row    int    `yaml:"row"`
+	Column int    `yaml:"column"`
+	Value  string `yaml:"value"`
+}
+
+// ColumnMapping maps YNAB fields to 0-based column indexes. Use Amount for a
+// signed column, or Outflow/Inflow for separate columns (outflow is negated).
+type ColumnMapping struct {
+	Date    int  `yaml:"date"`
+	Payee   *int `yaml:"payee"`
+	Memo    *int `yaml:"memo"`
+	Amount  *int `yaml:"amount"`
+	Outflow *int `yaml:"outflow"`
+	Inflow  *int `yaml:"inflow"`
+	Balance *int `yaml:"balance"` // Running balance, optional
+	Plan    *int `yaml:"plan"`    // Payment type of card statements, e.g. 1回払い or リボ
+}
+
+// ConfigParser is a Parser driven by a ParserDefinition
+type ConfigParser struct {
+	def ParserDefinition
+}
+
+// builtinDefinitions are the built-in parsers whose layout fits a
+// definition, a starting point for custom definitions
+//
+//go:embed builtin_parsers.yaml
+var builtinDefinitions []byte
+
+// BuiltinDefinitionsYAML returns the built-in parsers as a definitions file
+func BuiltinDefinitionsYAML() []byte {
+	return builtinDefinitions
+}
+
+// BuiltinDefinitions loads the definitions of BuiltinDefinitionsYAML
+func BuiltinDefinitions() ([]Parser, error) {
+	return parseDefinitions(builtinDefinitions, "builtin_parsers.yaml")
+}
+
+func LoadDefinitions(path string) ([]Parser, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseDefinitions(data, path)
+}
+
+func parseDefinitions(data []byte, path string) ([]Parser, error) {
+	var config ParserConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid parser definitions in %s: %w", path, err)
+	}
+
+	var loaded []Parser
+	for i, def := range config.Parsers {
+		if err := def.validate(); err != nil {
+			return nil, fmt.Errorf("parser definition #%d (%s) in %s: %w", i+1, def.Name, path, err)
+		}
+		loaded = append(loaded, ConfigParser{def: def})
+	}
+	return loaded, nil
+}
+
+func (def ParserDefinition) validate() error {
+	if def.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if def.DateLayout == "" {
+		return fmt.Errorf("date_layout is required")
+	}
+	if len(def.Header) == 0 && len(def.Match) == 0 && def.HeaderColumns == 0 {
+		return fmt.Errorf("one of header, match or header_columns is required")
+	}
+	cols := def.Columns
+	if cols.Amount == nil && cols.Outflow == nil && cols.Inflow == nil {
+		return fmt.Errorf("columns.amount or columns.outflow/inflow is required")
+	}
+	if cols.Amount != nil && (cols.Outflow != nil || cols.Inflow != nil) {
+		return fmt.Errorf("columns.amount cannot be combined with outflow/inflow")
+	}
+	if err := def.validateIndexes(); err != nil {
+		return err
+	}
+	for _, row := range append([]int{def.HeaderRow, def.SkipRows}, matchRows(def.Match)...) {
+		if row >= DetectRows {
+			return fmt.Errorf("header_row, skip_rows and match rows must be below %d, the rows read for detection", DetectRows)
+		}
+	}
+	if def.AccountType != "" && def.AccountType != AccountTypeBank && def.AccountType != AccountTypeCreditCard {
+		return fmt.Errorf("account_type must be %q or %q", AccountTypeBank, AccountTypeCreditCard)
+	}
+	return nil
+}
+
+// validateIndexes rejects negative rows and columns, which ParseRow and
+// Detect would index with
+func (def ParserDefinition) validateIndexes() error {
+	fields := []struct {
+		name  string
+		value *int
+	}{
+		{"header_row", &def.HeaderRow},
+		{"header_columns", &def.HeaderColumns},
+		{"skip_rows", &def.SkipRows},
+		{"columns.date", &def.Columns.Date},
+		{"columns.payee", def.Columns.Payee},
+		{"columns.memo", def.Columns.Memo},
+		{"columns.amount", def.Columns.Amount},
+		{"columns.outflow", def.Columns.Outflow},
+		{"columns.inflow", def.Columns.Inflow},
+		{"columns.balance", def.Columns.Balance},
+		{"columns.plan", def.Columns.Plan},
+	}
+	for _, field := range fields {
+		if field.value != nil && *field.value < 0 {
+			return fmt.Errorf("%s is %d, must not be negative", field.name, *field.value)
+		}
+	}
+	for i, index := range def.Required {
+		if index < 0 {
+			return fmt.Errorf("required[%d] is %d, must not be negative", i, index)
+		}
+	}
+	for i, m := range def.Match {
+		if m.Row < 0 || m.Column < 0 {
+			return fmt.Errorf("match #%d has row %d and column %d, must not be negative", i+1, m.Row, m.Column)
+		}
+	}
+	return nil
+}
+
+func matchRows(matches []CellMatch) []int {
+	rows := make([]int, len(matches))
+	for i, m := range matches {
+		rows[i] = m.Row
+	}
+	return rows
+}
+
+func (def ParserDefinition) currency() string {
+	if def.Currency == "" {
+		return "JPY"
+	}
+	return def.Currency
+}
+
+func (p ConfigParser) Name() string {
+	return p.def.Name
+}
+
+func (p ConfigParser) AccountType() string {
+	return p.def.AccountType
+}
+
+// Detect scores a full header as certain and cell matches as a layout match
+func (p ConfigParser) Detect(records [][]string) Detection {
+	def := p.def
+	if len(records) <= def.HeaderRow || len(records) <= def.SkipRows {
+		return rejected("too few rows: want more than %d, got %d", max(def.HeaderRow, def.SkipRows), len(records))
+	}
+
+	score := ScoreLayout
+	header := records[def.HeaderRow]
+	if len(def.Header) > 0 {
+		if detection := detectHeader(records, def.HeaderRow, def.Header); detection.Score == ScoreNone {
+			return detection
+		}
+		score = ScoreHeader
+	}
+	if def.HeaderColumns > 0 && len(header) != def.HeaderColumns {
+		return rejected("header has %d columns, want %d", len(header), def.HeaderColumns)
+	}
+	for _, m := range def.Match {
+		if cell, _ := cellAt(records, m.Row, m.Column); cell != m.Value {
+			return rejected("row %d column %d is %q, want %q", m.Row+1, m.Column+1, cell, m.Value)
+		}
+	}
+	return matched(score)
+}
+
+// HeaderRows is SkipRows
+func (p ConfigParser) HeaderRows() int {
+	return p.def.SkipRows
+}
+
+func (p ConfigParser) ParseRow(row []string) (*Transaction, error) {
+	def := p.def
+	cols := def.Columns
+
+	cell := func(index *int) string {
+		if index == nil || *index >= len(row) {
+			return ""
+		}
+		return row[*index]
+	}
+
+	if maxColumn := cols.maxIndex(); maxColumn >= len(row) {
+		return nil, fmt.Errorf("row has %d column(s), need at least %d", len(row), maxColumn+1)
+	}
+
+	for _, index := range def.Required {
+		if index >= len(row) || row[index] == "" {
+			return nil, fmt.Errorf("missing required fields")
+		}
+	}
+
+	date, err := convertDate(def.DateLayout, "2006-01-02", row[cols.Date])
+	if err != nil {
+		return nil, err
+	}
+
+	var amount Money
+	if cols.Amount != nil {
+		amount, err = ParseMoney(cell(cols.Amount), def.currency())
+	} else {
+		amount, err = parseOutflowInflow(cell(cols.Outflow), cell(cols.Inflow), def.currency())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	record := Transaction{
+		Date:   date,
+		Amount: amount,
+		Payee:  cell(cols.Payee),
+		Memo:   cell(cols.Memo),
+	}
+	if cols.Balance != nil {
+		record.Balance = parseBalance(cell(cols.Balance), def.currency())
+	}
+	if cols.Plan != nil {
+		record.Plan = parsePaymentPlan(cell(cols.Plan))
+	}
+	return &record, nil
+}
+
+func (c ColumnMapping) maxIndex() int {
+	max := c.Date
+	for _, index := range []*int{c.Payee, c.Memo, c.Amount, c.Outflow, c.Inflow, c.Balance, c.Plan} {
+		if index != nil && *index > max {
+			max = *index
+		}
+	}
+	return max
+}
@@ -0,0 +1,181 @@
+package parsers
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"cppcho.com/ynab_import/encoding"
+)
+
+func loadBuiltinDefinitions(t *testing.T) map[string]Parser {
+	t.Helper()
+	defined, err := BuiltinDefinitions()
+	if err != nil {
+		t.Fatalf("BuiltinDefinitions() error = %v", err)
+	}
+	byName := map[string]Parser{}
+	for _, p := range defined {
+		byName[p.Name()] = p
+	}
+	return byName
+}
+
+func TestConfigParser_MatchesBuiltinParsers(t *testing.T) {
+	defined := loadBuiltinDefinitions(t)
+
+	viewRecords := [][]string{
+		{"会員番号", "****"},
+		{"氏名", "Test"},
+		{""},
+		{""},
+		{"ご利用年月日", "ご利用箇所", "ご利用額", "払戻額", "ご請求額"},
+		{"", "", "", "", ""},
+		{"2025/01/05", "Test Store", "1000", "", "1000"},
+		{"2025/01/06", "Another Store", "2000", "", "2000"},
+	}
+	saisonRecords := [][]string{
+		{"カード名称", "Saison Card"},
+		{"お支払日", "2025/02/04"},
+		{"今回ご請求額", "3000"},
+		{"利用日", "ご利用店名及び商品名", "本人・家族区分", "支払区分名称", "利用金額", "支払金額"},
+		{"2025/01/05", "Test Store", "本人", "1回", "1000", "1000"},
+		{"bad-date", "Bad Store", "本人", "1回", "2000", "2000"},
+	}
+	rakutenCardRecords := [][]string{
+		{"利用日", "利用店名・商品名", "利用者", "支払方法", "利用金額", "支払手数料", "支払総額", "支払月", "支払残高", "新規サイン"},
+		{"2025/01/05", "Test Store", "本人", "1回払い", "1000", "0", "1000", "2025/02", "0", "*"},
+	}
+
+	tests := []struct {
+		name    string
+		builtin Parser
+		file    string
+		records [][]string
+	}{
+		{name: "smbc", builtin: Smbc{}, file: "testdata/smbc_valid.csv"},
+		{name: "rakuten", builtin: Rakuten{}, file: "testdata/rakuten_valid.csv"},
+		{name: "sbi", builtin: Sbi{}, file: "testdata/sbi_valid.csv"},
+		{name: "epos", builtin: Epos{}, file: "testdata/epos_valid.csv"},
+		{name: "view", builtin: View{}, records: viewRecords},
+		{name: "saison", builtin: Saison{}, records: saisonRecords},
+		{name: "rakuten_card", builtin: RakutenCard{}, records: rakutenCardRecords},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records := tt.records
+			if tt.file != "" {
+				var err error
+				records, err = encoding.ReadCSVFile(tt.file)
+				if err != nil {
+					t.Fatalf("encoding.ReadCSVFile() error = %v", err)
+				}
+			}
+
+			want, err := Parse(tt.builtin, records)
+			if err != nil || want == nil {
+				t.Fatalf("built-in Parse() = %v, %v", want, err)
+			}
+			got, err := Parse(defined[tt.name], records)
+			if err != nil || got == nil {
+				t.Fatalf("definition Parse() = %v, %v", got, err)
+			}
+
+			if !reflect.DeepEqual(got.ValidRecords, want.ValidRecords) {
+				t.Errorf("ValidRecords = %v, want %v", got.ValidRecords, want.ValidRecords)
+			}
+			if len(got.SkippedRows) != len(want.SkippedRows) {
+				t.Fatalf("got %d skipped rows, want %d", len(got.SkippedRows), len(want.SkippedRows))
+			}
+			for i := range got.SkippedRows {
+				if got.SkippedRows[i].RowNumber != want.SkippedRows[i].RowNumber {
+					t.Errorf("SkippedRow[%d].RowNumber = %d, want %d", i, got.SkippedRows[i].RowNumber, want.SkippedRows[i].RowNumber)
+				}
+			}
+		})
+	}
+}
+
+func TestConfigParser_NoMatch(t *testing.T) {
+	defined := loadBuiltinDefinitions(t)
+
+	records, err := encoding.ReadCSVFile("testdata/rakuten_valid.csv")
+	if err != nil {
+		t.Fatalf("encoding.ReadCSVFile() error = %v", err)
+	}
+
+	for _, name := range []string{"smbc", "view", "rakuten_card"} {
+		result, err := Parse(defined[name], records)
+		if err != nil || result != nil {
+			t.Errorf("%s Parse() = %v, %v; want nil, nil", name, result, err)
+		}
+	}
+
+	if result, err := Parse(defined["view"], [][]string{}); err != nil || result != nil {
+		t.Errorf("Parse() on empty records = %v, %v; want nil, nil", result, err)
+	}
+}
+
+func TestConfigParser_ShortRowSkipped(t *testing.T) {
+	defined := loadBuiltinDefinitions(t)
+
+	records := [][]string{
+		{"年月日", "お引出し", "お預入れ", "お取り扱い内容", "残高", "メモ", "ラベル"},
+		{"2025/1/5", "", "1000", "Valid", "10000", "", ""},
+		{"合計", "1000"}, // Trailing summary line
+	}
+
+	result, err := Parse(defined["smbc"], records)
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+	if len(result.ValidRecords) != 1 || len(result.SkippedRows) != 1 {
+		t.Fatalf("Parse() = %d valid, %d skipped; want 1, 1", len(result.ValidRecords), len(result.SkippedRows))
+	}
+	if result.SkippedRows[0].RowNumber != 3 {
+		t.Errorf("SkippedRow[0].RowNumber = %d, want 3", result.SkippedRows[0].RowNumber)
+	}
+}
+
+func TestLoadParserDefinitions_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"missing name", "parsers:\n  - date_layout: \"2006/01/02\"\n    header: [a]\n    columns: {date: 0, amount: 1}\n", "name is required"},
+		{"missing layout", "parsers:\n  - name: x\n    header: [a]\n    columns: {date: 0, amount: 1}\n", "date_layout is required"},
+		{"no detection", "parsers:\n  - name: x\n    date_layout: \"2006/01/02\"\n    columns: {date: 0, amount: 1}\n", "one of header"},
+		{"no amount", "parsers:\n  - name: x\n    date_layout: \"2006/01/02\"\n    header: [a]\n    columns: {date: 0}\n", "columns.amount"},
+		{"amount and outflow", "parsers:\n  - name: x\n    date_layout: \"2006/01/02\"\n    header: [a]\n    columns: {date: 0, amount: 1, outflow: 2}\n", "cannot be combined"},
+		{"skip beyond detection", "parsers:\n  - name: x\n    date_layout: \"2006/01/02\"\n    header: [a]\n    skip_rows: 20\n    columns: {date: 0, amount: 1}\n", "must be below 20"},
+		{"negative column", "parsers:\n  - name: x\n    date_layout: \"2006/01/02\"\n    header: [a]\n    columns: {date: 0, payee: -1, amount: 1}\n", "columns.payee is -1, must not be negative"},
+		{"negative date column", "parsers:\n  - name: x\n    date_layout: \"2006/01/02\"\n    header: [a]\n    columns: {date: -2, amount: 1}\n", "columns.date is -2"},
+		{"negative required", "parsers:\n  - name: x\n    date_layout: \"2006/01/02\"\n    header: [a]\n    required: [0, -1]\n    columns: {date: 0, amount: 1}\n", "required[1] is -1"},
+		{"negative match column", "parsers:\n  - name: x\n    date_layout: \"2006/01/02\"\n    match: [{row: 0, column: -1, value: a}]\n    columns: {date: 0, amount: 1}\n", "match #1 has row 0 and column -1"},
+		{"negative skip rows", "parsers:\n  - name: x\n    date_layout: \"2006/01/02\"\n    header: [a]\n    skip_rows: -1\n    columns: {date: 0, amount: 1}\n", "skip_rows is -1"},
+		{"bad yaml", "parsers: [", "invalid parser definitions"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "parsers.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("WriteFile() error = %v", err)
+			}
+			_, err := LoadDefinitions(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadDefinitions() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadParserDefinitions_FileNotFound(t *testing.T) {
+	if _, err := LoadDefinitions("testdata/config/does_not_exist.yaml"); err == nil {
+		t.Error("LoadDefinitions() expected error for non-existent file, got nil")
+	}
+}
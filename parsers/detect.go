@@ -0,0 +1,166 @@
+package parsers
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	"cppcho.com/ynab_import/encoding"
+)
+
+// Detection scores, higher is more certain
+const (
+	ScoreNone   = 0
+	ScoreWeak   = 50  // A few loosely checked cells
+	ScoreLayout = 80  // Distinctive cells in several rows
+	ScoreHeader = 100 // The complete header row
+)
+
+// Detection is a parser's verdict on whether rows are in its format.
+// Reason names the check that rejected the rows when Score is ScoreNone,
+// Details can explain it further (e.g. a header diff, one line per column).
+type Detection struct {
+	Score   int
+	Reason  string
+	Details []string
+}
+
+// Candidate is a parser that recognized the rows
+type Candidate struct {
+	Parser Parser
+	Detection
+}
+
+func rejected(format string, args ...any) Detection {
+	return Detection{Score: ScoreNone, Reason: fmt.Sprintf(format, args...)}
+}
+
+func matched(score int) Detection {
+	return Detection{Score: score}
+}
+
+// detectHeader matches when records[row] equals one of headers exactly
+func detectHeader(records [][]string, row int, headers ...[]string) Detection {
+	if len(records) <= row {
+		return rejected("too few rows: want more than %d, got %d", row, len(records))
+	}
+	for _, header := range headers {
+		if reflect.DeepEqual(records[row], header) {
+			return matched(ScoreHeader)
+		}
+	}
+
+	// Explain the difference to the closest of the accepted headers
+	var diff []string
+	var closest []string
+	for _, header := range headers {
+		if d := headerDiff(records[row], header); closest == nil || len(d) < len(diff) {
+			diff, closest = d, header
+		}
+	}
+	detection := rejected("header mismatch in row %d: %d difference(s) from the expected %d columns", row+1, len(diff), len(closest))
+	// A column by column diff only helps when the layout is similar
+	if len(diff) <= len(closest)/2 {
+		detection.Details = diff
+	}
+	return detection
+}
+
+// headerDiff lists the columns of got that differ from want
+func headerDiff(got, want []string) []string {
+	var diff []string
+	for i := 0; i < max(len(got), len(want)); i++ {
+		switch {
+		case i >= len(want):
+			diff = append(diff, fmt.Sprintf("column %d: got %q, want no column", i+1, got[i]))
+		case i >= len(got):
+			diff = append(diff, fmt.Sprintf("column %d: missing, want %q", i+1, want[i]))
+		case got[i] != want[i]:
+			diff = append(diff, fmt.Sprintf("column %d: got %q, want %q", i+1, got[i], want[i]))
+		}
+	}
+	return diff
+}
+
+// cellAt returns records[row][col], and false when the cell doesn't exist
+func cellAt(records [][]string, row, col int) (string, bool) {
+	if row >= len(records) || col >= len(records[row]) {
+		return "", false
+	}
+	return records[row][col], true
+}
+
+// Detect reads a CSV export from r and parses it with the built-in parser
+// most certain to recognize it. The transactions get their import IDs. The
+// parser is nil when no parser matched.
+func Detect(r io.Reader) (Parser, *ParseResult, error) {
+	rows, _, err := encoding.NewCSVReader(r, "")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+	return MatchRows(Builtin(), rows)
+}
+
+// Rank returns the parsers of list that recognize records, most certain
+// first; parsers with the same score keep their order in list
+func Rank(list []Parser, records [][]string) []Candidate {
+	var candidates []Candidate
+	for _, parser := range list {
+		if detection := SafeDetect(parser, records); detection.Score > ScoreNone {
+			candidates = append(candidates, Candidate{Parser: parser, Detection: detection})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	return candidates
+}
+
+// Match parses records with the best ranked parser of list. The parser is
+// nil when no parser matched.
+func Match(list []Parser, records [][]string) (Parser, *ParseResult, error) {
+	return MatchRows(list, SliceRows(records))
+}
+
+// MatchRows is Match for rows read one at a time: only the first
+// DetectRows rows are held for detection
+func MatchRows(list []Parser, rows RowReader) (Parser, *ParseResult, error) {
+	head, rows, err := Peek(rows, DetectRows)
+	if err != nil {
+		return nil, nil, err
+	}
+	candidates := Rank(list, head)
+	if len(candidates) == 0 {
+		return nil, nil, nil
+	}
+	parser := candidates[0].Parser
+	parsed, err := ParseRows(parser, rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	return parser, parsed, nil
+}
+
+// Parse parses records with parser and assigns import IDs. The result is
+// nil when the records are not in the parser's format.
+func Parse(parser Parser, records [][]string) (*ParseResult, error) {
+	head, rows, err := Peek(SliceRows(records), DetectRows)
+	if err != nil {
+		return nil, err
+	}
+	if SafeDetect(parser, head).Score == ScoreNone {
+		return nil, nil // Not its format
+	}
+	return ParseRows(parser, rows)
+}
+
+// SafeDetect runs parser.Detect, turning a panic into a rejection
+func SafeDetect(parser Parser, records [][]string) (detection Detection) {
+	defer func() {
+		if r := recover(); r != nil {
+			detection = rejected("detection panicked: %v", r)
+		}
+	}()
+	return parser.Detect(records)
+}
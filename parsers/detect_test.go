@@ -0,0 +1,216 @@
+package parsers
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"cppcho.com/ynab_import/encoding"
+)
+
+// Every built-in CSV fixture with the parser that reads it
+var fixtureParsers = []struct {
+	parser Parser
+	file   string
+}{
+	{Smbc{}, "testdata/smbc_valid.csv"},
+	{Rakuten{}, "testdata/rakuten_valid.csv"},
+	{Sbi{}, "testdata/sbi_valid.csv"},
+	{Shinsei{}, "testdata/shinsei_valid.csv"},
+	{Epos{}, "testdata/epos_valid.csv"},
+	{SmbcCard{}, "testdata/smbc_card_valid.csv"},
+	{PayPay{}, "testdata/paypay_valid.csv"},
+}
+
+// parseFixture parses a testdata file with parser, which also assigns import IDs
+func parseFixture(t *testing.T, parser Parser, file string) *ParseResult {
+	t.Helper()
+	records, err := encoding.ReadCSVFile(file)
+	if err != nil {
+		t.Fatalf("ReadCSVFile() error = %v", err)
+	}
+	result, err := Parse(parser, records)
+	if err != nil || result == nil {
+		t.Fatalf("Parse() = %v, %v", result, err)
+	}
+	return result
+}
+
+func TestDetect(t *testing.T) {
+	for _, fixture := range fixtureParsers {
+		t.Run(fixture.parser.Name(), func(t *testing.T) {
+			f, err := os.Open(fixture.file)
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+			defer f.Close()
+
+			parser, result, err := Detect(f)
+			if err != nil {
+				t.Fatalf("Detect() error = %v", err)
+			}
+			if parser == nil || parser.Name() != fixture.parser.Name() {
+				t.Fatalf("Detect() parser = %v, want %s", parser, fixture.parser.Name())
+			}
+			if len(result.ValidRecords) == 0 {
+				t.Fatal("Detect() returned no transactions")
+			}
+			for i, record := range result.ValidRecords {
+				if record.ImportID == "" {
+					t.Errorf("Transaction[%d] has no import ID", i)
+				}
+			}
+		})
+	}
+}
+
+func TestDetect_NoMatch(t *testing.T) {
+	f, err := os.Open("../encoding/testdata/utf8_simple.csv")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer f.Close()
+
+	parser, result, err := Detect(f)
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+	if parser != nil || result != nil {
+		t.Errorf("Detect() = %v, %v, want no match", parser, result)
+	}
+}
+
+func TestRank_Fixtures(t *testing.T) {
+	// Every fixture should be claimed by exactly one built-in parser
+	for _, fixture := range fixtureParsers {
+		t.Run(fixture.parser.Name(), func(t *testing.T) {
+			records, err := encoding.ReadCSVFile(fixture.file)
+			if err != nil {
+				t.Fatalf("ReadCSVFile() error = %v", err)
+			}
+			candidates := Rank(Builtin(), records)
+			if len(candidates) != 1 || candidates[0].Parser.Name() != fixture.parser.Name() {
+				var names []string
+				for _, c := range candidates {
+					names = append(names, c.Parser.Name())
+				}
+				t.Errorf("Rank() = %v, want only %s", names, fixture.parser.Name())
+			}
+		})
+	}
+}
+
+func TestRank_Ambiguous(t *testing.T) {
+	// A holder row that satisfies both SMBC Card layouts
+	records := [][]string{{"山田太郎様", "1234-****-****-****", "ご本人", "", "", "'2025/12"}}
+
+	candidates := Rank(Builtin(), records)
+	if len(candidates) != 2 {
+		t.Fatalf("Rank() got %d candidate(s), want 2", len(candidates))
+	}
+	// Same score, so registry order decides
+	if candidates[0].Parser.Name() != "smbc_card" || candidates[1].Parser.Name() != "smbc_card2" {
+		t.Errorf("Rank() order = %s, %s, want smbc_card, smbc_card2", candidates[0].Parser.Name(), candidates[1].Parser.Name())
+	}
+}
+
+func TestRank_HeaderOutranksLayout(t *testing.T) {
+	definitions := []ParserDefinition{
+		{Name: "loose", DateLayout: "2006/01/02", Match: []CellMatch{{Row: 0, Column: 0, Value: "日付"}}},
+		{Name: "exact", DateLayout: "2006/01/02", Header: []string{"日付", "金額"}},
+	}
+	list := []Parser{ConfigParser{def: definitions[0]}, ConfigParser{def: definitions[1]}}
+
+	candidates := Rank(list, [][]string{{"日付", "金額"}, {"2025/12/01", "100"}})
+	if len(candidates) != 2 || candidates[0].Parser.Name() != "exact" {
+		t.Fatalf("Rank() = %v, want exact first", candidates)
+	}
+	if candidates[0].Score != ScoreHeader || candidates[1].Score != ScoreLayout {
+		t.Errorf("scores = %d, %d, want %d, %d", candidates[0].Score, candidates[1].Score, ScoreHeader, ScoreLayout)
+	}
+}
+
+func TestDetect_Reasons(t *testing.T) {
+	tests := []struct {
+		name    string
+		parser  Parser
+		records [][]string
+		reason  string
+	}{
+		{"empty", Smbc{}, nil, "too few rows"},
+		{"header mismatch", Sbi{}, [][]string{{"日付", "内容"}}, "header mismatch"},
+		{"view too short", View{}, [][]string{{"会員番号"}}, "too few rows: want more than 6, got 1"},
+		{"smbc card short row", SmbcCard{}, [][]string{{"a"}}, "row 1 column 3"},
+		{"rakuten card width", RakutenCard{}, [][]string{{"a", "b"}}, "header has 2 columns, want 10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detection := tt.parser.Detect(tt.records)
+			if detection.Score != ScoreNone {
+				t.Fatalf("Detect() score = %d, want %d", detection.Score, ScoreNone)
+			}
+			if !strings.Contains(detection.Reason, tt.reason) {
+				t.Errorf("Detect() reason = %q, want it to contain %q", detection.Reason, tt.reason)
+			}
+		})
+	}
+}
+
+func TestDetectHeader_Diff(t *testing.T) {
+	records := [][]string{{"年月日", "お引出し", "お預入れ金額", "お取り扱い内容", "残高", "メモ"}}
+
+	detection := Smbc{}.Detect(records)
+	if detection.Score != ScoreNone {
+		t.Fatalf("Detect() score = %d, want %d", detection.Score, ScoreNone)
+	}
+	expected := []string{
+		`column 3: got "お預入れ金額", want "お預入れ"`,
+		`column 7: missing, want "ラベル"`,
+	}
+	if !reflect.DeepEqual(detection.Details, expected) {
+		t.Errorf("Detect() details = %q, want %q", detection.Details, expected)
+	}
+
+	// Unrelated layouts only get the summary
+	if details := (Epos{}).Detect(records).Details; details != nil {
+		t.Errorf("Detect() details for an unrelated header = %q, want none", details)
+	}
+}
+
+// panicky detects everything and then crashes while parsing
+type panicky struct{ inDetect bool }
+
+func (p panicky) Name() string { return "panicky" }
+
+func (p panicky) Detect(records [][]string) Detection {
+	if p.inDetect {
+		_ = records[99][99]
+	}
+	return matched(ScoreHeader)
+}
+
+func (p panicky) HeaderRows() int { return 0 }
+
+func (p panicky) ParseRow(row []string) (*Transaction, error) {
+	_ = row[99]
+	return nil, nil
+}
+
+func TestParse_RecoversPanic(t *testing.T) {
+	records := [][]string{{"a"}}
+
+	_, err := Parse(panicky{}, records)
+	if err == nil || !strings.Contains(err.Error(), "parser panicky panicked") {
+		t.Errorf("Parse() error = %v, want a recovered panic", err)
+	}
+
+	detection := SafeDetect(panicky{inDetect: true}, records)
+	if detection.Score != ScoreNone || !strings.Contains(detection.Reason, "detection panicked") {
+		t.Errorf("SafeDetect() = %+v, want a rejection", detection)
+	}
+	if candidates := Rank([]Parser{panicky{inDetect: true}, Smbc{}}, records); len(candidates) != 0 {
+		t.Errorf("Rank() = %v, want no candidates", candidates)
+	}
+}
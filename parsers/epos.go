@@ -0,0 +1,48 @@
+package parsers
+
+import (
+	"fmt"
+)
+
+type Epos struct{}
+
+func (p Epos) Name() string {
+	return "epos"
+}
+
+func (p Epos) AccountType() string {
+	return AccountTypeCreditCard
+}
+
+func (p Epos) Detect(records [][]string) Detection {
+	return detectHeader(records, 0, []string{"種別（ショッピング、キャッシング、その他）", "ご利用年月日", "ご利用場所", "ご利用内容", "ご利用金額", "お支払金額（キャッシングでは利息を含みます）", "支払区分"})
+}
+
+func (p Epos) HeaderRows() int {
+	return 1 // The header
+}
+
+func (p Epos) ParseRow(row []string) (*Transaction, error) {
+	cols := columnsOf(row)
+	dateCell, payee, amountCell, paymentType := cols.get(1), cols.get(2), cols.get(5), cols.get(6)
+	if cols.err != nil {
+		return nil, cols.err
+	}
+	if dateCell == "" || paymentType == "" {
+		return nil, fmt.Errorf("missing required fields (date or payment type)")
+	}
+	date, err := convertDate("2006年01月02日", "2006-01-02", dateCell)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := ParseMoney(amountCell, "JPY")
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{
+		Date:   date,
+		Amount: amount.Neg(),
+		Payee:  payee,
+		Plan:   parsePaymentPlan(paymentType),
+	}, nil
+}
@@ -1,7 +1,9 @@
-package main
+package parsers
 
 import (
 	"testing"
+
+	"cppcho.com/ynab_import/encoding"
 )
 
 func TestEpos_Name(t *testing.T) {
@@ -12,13 +14,13 @@ func TestEpos_Name(t *testing.T) {
 }
 
 func TestEpos_Parse_ValidCSV(t *testing.T) {
-	records, err := readCsvToRawRecords("testdata/parsers/epos_valid.csv")
+	records, err := encoding.ReadCSVFile("testdata/epos_valid.csv")
 	if err != nil {
-		t.Fatalf("readCsvToRawRecords() error = %v", err)
+		t.Fatalf("encoding.ReadCSVFile() error = %v", err)
 	}
 
 	parser := Epos{}
-	result, err := parser.Parse(records)
+	result, err := Parse(parser, records)
 	if err != nil {
 		t.Fatalf("Parse() unexpected error: %v", err)
 	}
@@ -34,25 +36,25 @@ func TestEpos_Parse_ValidCSV(t *testing.T) {
 
 	// Verify first record
 	if len(result.ValidRecords) > 0 {
-		if result.ValidRecords[0].date != "2025-12-24" {
-			t.Errorf("Record[0].date = %q, want %q", result.ValidRecords[0].date, "2025-12-24")
+		if result.ValidRecords[0].Date != "2025-12-24" {
+			t.Errorf("Record[0].Date = %q, want %q", result.ValidRecords[0].Date, "2025-12-24")
 		}
 		// Amount should be flipped (negative)
-		if result.ValidRecords[0].amount != "-1155" {
-			t.Errorf("Record[0].amount = %q, want %q (flipSign applied)", result.ValidRecords[0].amount, "-1155")
+		if result.ValidRecords[0].Amount.String() != "-1155" {
+			t.Errorf("Record[0].Amount = %q, want %q (sign flipped)", result.ValidRecords[0].Amount.String(), "-1155")
 		}
 	}
 }
 
 func TestEpos_Parse_WrongHeaders(t *testing.T) {
 	// Use SMBC CSV (different headers)
-	records, err := readCsvToRawRecords("testdata/parsers/smbc_valid.csv")
+	records, err := encoding.ReadCSVFile("testdata/smbc_valid.csv")
 	if err != nil {
-		t.Fatalf("readCsvToRawRecords() error = %v", err)
+		t.Fatalf("encoding.ReadCSVFile() error = %v", err)
 	}
 
 	parser := Epos{}
-	result, err := parser.Parse(records)
+	result, err := Parse(parser, records)
 	if err != nil {
 		t.Errorf("Parse() unexpected error: %v", err)
 	}
@@ -71,7 +73,7 @@ func TestEpos_Parse_DateConversion(t *testing.T) {
 		{"ショッピング", "2025年01月05日", "Test Shop", "−", "1000", "1000", "1回払い"},
 	}
 
-	result, err := parser.Parse(mockRecords)
+	result, err := Parse(parser, mockRecords)
 	if err != nil {
 		t.Fatalf("Parse() unexpected error: %v", err)
 	}
@@ -80,8 +82,8 @@ func TestEpos_Parse_DateConversion(t *testing.T) {
 	}
 
 	if len(result.ValidRecords) > 0 {
-		if result.ValidRecords[0].date != "2025-01-05" {
-			t.Errorf("Date conversion failed: got %q, want %q", result.ValidRecords[0].date, "2025-01-05")
+		if result.ValidRecords[0].Date != "2025-01-05" {
+			t.Errorf("Date conversion failed: got %q, want %q", result.ValidRecords[0].Date, "2025-01-05")
 		}
 	}
 }
@@ -98,7 +100,7 @@ func TestEpos_Parse_EmptyRowSkipping(t *testing.T) {
 		{"ショッピング", "2025年01月07日", "Shop 4", "−", "4000", "4000", "1回払い"}, // Valid
 	}
 
-	result, err := parser.Parse(mockRecords)
+	result, err := Parse(parser, mockRecords)
 	if err != nil {
 		t.Fatalf("Parse() unexpected error: %v", err)
 	}
@@ -130,7 +132,7 @@ func TestEpos_Parse_FullWidthHyphenSkipping(t *testing.T) {
 		{"ショッピング", "2025年01月07日", "Shop 3", "−", "3000", "3000", "1回払い"}, // Valid
 	}
 
-	result, err := parser.Parse(mockRecords)
+	result, err := Parse(parser, mockRecords)
 	if err != nil {
 		t.Fatalf("Parse() unexpected error: %v", err)
 	}
@@ -150,14 +152,14 @@ func TestEpos_Parse_FullWidthHyphenSkipping(t *testing.T) {
 
 	// Verify the valid records are correct
 	if len(result.ValidRecords) == 3 {
-		if result.ValidRecords[0].payee != "Shop 1" {
-			t.Errorf("Record[0].payee = %q, want %q", result.ValidRecords[0].payee, "Shop 1")
+		if result.ValidRecords[0].Payee != "Shop 1" {
+			t.Errorf("Record[0].Payee = %q, want %q", result.ValidRecords[0].Payee, "Shop 1")
 		}
-		if result.ValidRecords[1].payee != "Shop 2" {
-			t.Errorf("Record[1].payee = %q, want %q", result.ValidRecords[1].payee, "Shop 2")
+		if result.ValidRecords[1].Payee != "Shop 2" {
+			t.Errorf("Record[1].Payee = %q, want %q", result.ValidRecords[1].Payee, "Shop 2")
 		}
-		if result.ValidRecords[2].payee != "Shop 3" {
-			t.Errorf("Record[2].payee = %q, want %q", result.ValidRecords[2].payee, "Shop 3")
+		if result.ValidRecords[2].Payee != "Shop 3" {
+			t.Errorf("Record[2].Payee = %q, want %q", result.ValidRecords[2].Payee, "Shop 3")
 		}
 	}
 }
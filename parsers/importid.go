@@ -0,0 +1,56 @@
+package parsers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// YNAB rejects import IDs longer than 36 characters
+const maxImportIDLength = 36
+
+// AssignImportIDs gives every record a deterministic import ID in YNAB's
+// "YNAB:amount:date:occurrence" style, with the parser name in place of
+// "YNAB" (e.g. smbc:-23000000:2025-12-26:1). The occurrence counts identical
+// amount/date pairs within the file, so re-importing the same export (or an
+// overlapping one) always produces the same IDs.
+func AssignImportIDs(parserName string, records []Transaction) {
+	ids := newImportIDs(parserName)
+	for i := range records {
+		ids.assign(&records[i])
+	}
+}
+
+// importIDs assigns the IDs of AssignImportIDs one record at a time, in
+// file order
+type importIDs struct {
+	parserName  string
+	occurrences map[string]int
+}
+
+func newImportIDs(parserName string) importIDs {
+	return importIDs{parserName: parserName, occurrences: map[string]int{}}
+}
+
+func (ids importIDs) assign(record *Transaction) {
+	if record.Date == "" {
+		return
+	}
+	key := fmt.Sprintf("%d:%s", record.Amount.Milliunits, record.Date)
+	ids.occurrences[key]++
+	record.ImportID = buildImportID(ids.parserName, fmt.Sprintf("%s:%d", key, ids.occurrences[key]))
+}
+
+// Hex digits of the hash that replaces a parser name too long for an ID
+const importIDHashLength = 8
+
+func buildImportID(prefix, suffix string) string {
+	// Replace the prefix rather than shorten the suffix so IDs stay unique per
+	// transaction. A truncated name could collide (smbc_card and smbc_card2),
+	// a hash of the whole name doesn't.
+	if room := maxImportIDLength - len(suffix) - 1; len(prefix) > room {
+		sum := sha256.Sum256([]byte(prefix))
+		prefix = hex.EncodeToString(sum[:])[:min(importIDHashLength, max(room, 0))]
+	}
+	return prefix + ":" + suffix
+}
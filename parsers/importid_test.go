@@ -0,0 +1,87 @@
+package parsers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAssignImportIDs(t *testing.T) {
+	records := []Transaction{
+		{Date: "2025-12-26", Amount: Yen(-23000)},
+		{Date: "2025-12-26", Amount: Yen(-23000)}, // Same amount/date, second occurrence
+		{Date: "2025-12-26", Amount: Yen(31113)},
+		{Date: "2025-12-27", Amount: Yen(-23000)},
+		{Date: "", Amount: Yen(100)}, // No date, no ID
+	}
+
+	AssignImportIDs("smbc", records)
+
+	expected := []string{
+		"smbc:-23000000:2025-12-26:1",
+		"smbc:-23000000:2025-12-26:2",
+		"smbc:31113000:2025-12-26:1",
+		"smbc:-23000000:2025-12-27:1",
+		"",
+	}
+	for i, want := range expected {
+		if records[i].ImportID != want {
+			t.Errorf("Record[%d].ImportID = %q, want %q", i, records[i].ImportID, want)
+		}
+	}
+}
+
+func TestAssignImportIDs_Deterministic(t *testing.T) {
+	// Parse assigns the IDs
+	first := parseFixture(t, Shinsei{}, "testdata/shinsei_valid.csv")
+	second := parseFixture(t, Shinsei{}, "testdata/shinsei_valid.csv")
+
+	seen := map[string]bool{}
+	for i := range first.ValidRecords {
+		id := first.ValidRecords[i].ImportID
+		if id != second.ValidRecords[i].ImportID {
+			t.Errorf("Record[%d] import ID changed between runs: %q vs %q", i, id, second.ValidRecords[i].ImportID)
+		}
+		if seen[id] {
+			t.Errorf("Record[%d] import ID %q is not unique", i, id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestBuildImportID_MaxLength(t *testing.T) {
+	id := buildImportID("a_very_long_parser_name", "-123456789000:2025-12-26:1")
+	if len(id) > maxImportIDLength {
+		t.Errorf("buildImportID() = %q (%d chars), want at most %d", id, len(id), maxImportIDLength)
+	}
+	if !strings.HasSuffix(id, ":-123456789000:2025-12-26:1") {
+		t.Errorf("buildImportID() = %q, want suffix kept intact", id)
+	}
+	if again := buildImportID("a_very_long_parser_name", "-123456789000:2025-12-26:1"); again != id {
+		t.Errorf("buildImportID() = %q then %q, want the same ID", id, again)
+	}
+}
+
+func TestBuildImportID_LongPrefixesDoNotCollide(t *testing.T) {
+	names := []string{"smbc_card", "smbc_card2"}
+	for _, parser := range Builtin() {
+		names = append(names, parser.Name())
+	}
+	for _, parser := range BuiltinPDF() {
+		names = append(names, parser.Name())
+	}
+
+	// Large enough that every name but the shortest has to be shortened
+	for _, suffix := range []string{"-123456789000:2025-12-26:1", "-1234567890000:2025-12-26:1:3"} {
+		seen := map[string]string{}
+		for _, name := range names {
+			id := buildImportID(name, suffix)
+			if len(id) > maxImportIDLength {
+				t.Errorf("buildImportID(%q) = %q, longer than %d", name, id, maxImportIDLength)
+			}
+			if other, ok := seen[id]; ok && other != name {
+				t.Errorf("buildImportID(%q) = buildImportID(%q) = %q", name, other, id)
+			}
+			seen[id] = name
+		}
+	}
+}
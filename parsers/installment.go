@@ -0,0 +1,154 @@
+package parsers
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Kinds of credit card payment plans
+const (
+	PlanLump         = "lump"         // 1回払い, 一括
+	PlanInstallments = "installments" // 分割, N回払い
+	PlanRevolving    = "revolving"    // リボ
+	PlanBonus        = "bonus"        // ボーナス一括
+)
+
+// PaymentPlan is how a credit card purchase is paid back
+type PaymentPlan struct {
+	Kind         string // One of the Plan constants, empty when the export has none
+	Label        string // As printed, e.g. 分割 or 3回払い
+	Count        int    // Number of payments of PlanInstallments, 0 when unknown
+	FirstPayment string // YYYY-MM of the first payment, when the export has it
+}
+
+// Summary describes plans other than a single payment, e.g.
+// "3 installments from 2026-01" or "revolving"
+func (p PaymentPlan) Summary() string {
+	var summary string
+	switch p.Kind {
+	case PlanInstallments:
+		summary = "installments"
+		if p.Count > 0 {
+			summary = fmt.Sprintf("%d installments", p.Count)
+		}
+	case PlanRevolving:
+		summary = "revolving"
+	case PlanBonus:
+		summary = "bonus payment"
+	default:
+		return ""
+	}
+	if p.FirstPayment != "" {
+		summary += " from " + p.FirstPayment
+	}
+	return summary
+}
+
+var paymentCountPattern = regexp.MustCompile(`(\d+)\s*回`)
+
+// parsePaymentPlan reads a payment type cell such as 1回払い, 分割(3回),
+// リボ払い or ボーナス一括
+func parsePaymentPlan(label string) PaymentPlan {
+	label = strings.TrimSpace(label)
+	plan := PaymentPlan{Label: label}
+	text := norm.NFKC.String(label)
+
+	count := 0
+	if m := paymentCountPattern.FindStringSubmatch(text); m != nil {
+		count, _ = strconv.Atoi(m[1])
+	}
+	switch {
+	case label == "":
+	case strings.Contains(text, "リボ"):
+		plan.Kind = PlanRevolving
+	case strings.Contains(text, "ボーナス"):
+		plan.Kind = PlanBonus
+	case strings.Contains(text, "分割") || count > 1:
+		plan.Kind = PlanInstallments
+		plan.Count = count
+	case count == 1 || strings.Contains(text, "一括"):
+		plan.Kind = PlanLump
+	}
+	return plan
+}
+
+var paymentMonthPattern = regexp.MustCompile(`^'?(\d{2}|\d{4})/(\d{1,2})$`)
+
+// parsePaymentMonth reads a payment month such as '26/01 as 2026-01
+func parsePaymentMonth(cell string) string {
+	m := paymentMonthPattern.FindStringSubmatch(strings.TrimSpace(cell))
+	if m == nil {
+		return ""
+	}
+	year, _ := strconv.Atoi(m[1])
+	month, _ := strconv.Atoi(m[2])
+	if month < 1 || month > 12 {
+		return ""
+	}
+	if year < 100 {
+		year += 2000
+	}
+	return fmt.Sprintf("%04d-%02d", year, month)
+}
+
+// Installments splits a purchase paid in installments into one record per
+// payment, dated on the purchase day of each payment month. Payments start
+// in FirstPayment, or the month after the purchase when the export doesn't
+// say; the remainder of an uneven split goes on the first payment. Other
+// records are returned unchanged.
+func (r Transaction) Installments() []Transaction {
+	plan := r.Plan
+	if plan.Kind != PlanInstallments || plan.Count < 2 || r.Date == "" {
+		return []Transaction{r}
+	}
+	date, err := time.Parse("2006-01-02", r.Date)
+	if err != nil {
+		return []Transaction{r}
+	}
+	first := time.Date(date.Year(), date.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	if month, err := time.Parse("2006-01", plan.FirstPayment); err == nil {
+		first = month
+	}
+
+	// Split whole currency units so yen amounts stay whole
+	count := int64(plan.Count)
+	units := r.Amount.Milliunits / 1000
+	remainder := r.Amount.Milliunits - units/count*count*1000
+
+	prefix, suffix, _ := strings.Cut(r.ImportID, ":")
+	payments := make([]Transaction, plan.Count)
+	for i := range payments {
+		month := first.AddDate(0, i, 0)
+		day := min(date.Day(), daysIn(month))
+
+		payment := r
+		payment.Date = time.Date(month.Year(), month.Month(), day, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
+		payment.Amount.Milliunits = units / count * 1000
+		if i == 0 {
+			payment.Amount.Milliunits += remainder
+		}
+		payment.Memo = AppendMemo(r.Memo, fmt.Sprintf("installment %d/%d", i+1, plan.Count))
+		if r.ImportID != "" {
+			payment.ImportID = buildImportID(prefix, fmt.Sprintf("%s:%d", suffix, i+1))
+		}
+		payments[i] = payment
+	}
+	return payments
+}
+
+// AppendMemo adds a note to a memo, separated by a comma
+func AppendMemo(memo, note string) string {
+	if memo == "" {
+		return note
+	}
+	return memo + ", " + note
+}
+
+func daysIn(month time.Time) int {
+	return time.Date(month.Year(), month.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
@@ -0,0 +1,108 @@
+package parsers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePaymentPlan(t *testing.T) {
+	tests := []struct {
+		label    string
+		expected PaymentPlan
+	}{
+		{"1回払い", PaymentPlan{Kind: PlanLump, Label: "1回払い"}},
+		{"１回払い", PaymentPlan{Kind: PlanLump, Label: "１回払い"}},
+		{"一括", PaymentPlan{Kind: PlanLump, Label: "一括"}},
+		{"3回払い", PaymentPlan{Kind: PlanInstallments, Label: "3回払い", Count: 3}},
+		{"分割(12回)", PaymentPlan{Kind: PlanInstallments, Label: "分割(12回)", Count: 12}},
+		{"分割", PaymentPlan{Kind: PlanInstallments, Label: "分割"}},
+		{"リボ払い", PaymentPlan{Kind: PlanRevolving, Label: "リボ払い"}},
+		{"ﾘﾎﾞ", PaymentPlan{Kind: PlanRevolving, Label: "ﾘﾎﾞ"}},
+		{"ボーナス一括", PaymentPlan{Kind: PlanBonus, Label: "ボーナス一括"}},
+		{"その他", PaymentPlan{Label: "その他"}},
+		{"", PaymentPlan{}},
+	}
+
+	for _, tt := range tests {
+		if got := parsePaymentPlan(tt.label); got != tt.expected {
+			t.Errorf("parsePaymentPlan(%q) = %+v, want %+v", tt.label, got, tt.expected)
+		}
+	}
+}
+
+func TestParsePaymentMonth(t *testing.T) {
+	tests := map[string]string{
+		"'26/01":  "2026-01",
+		"2026/3":  "2026-03",
+		"'26/13":  "",
+		"":        "",
+		"1回払い":    "",
+		"26/01/5": "",
+	}
+	for cell, expected := range tests {
+		if got := parsePaymentMonth(cell); got != expected {
+			t.Errorf("parsePaymentMonth(%q) = %q, want %q", cell, got, expected)
+		}
+	}
+}
+
+func TestPaymentPlan_Summary(t *testing.T) {
+	tests := []struct {
+		plan     PaymentPlan
+		expected string
+	}{
+		{PaymentPlan{Kind: PlanLump, FirstPayment: "2026-01"}, ""},
+		{PaymentPlan{Kind: PlanInstallments, Count: 3, FirstPayment: "2026-01"}, "3 installments from 2026-01"},
+		{PaymentPlan{Kind: PlanInstallments}, "installments"},
+		{PaymentPlan{Kind: PlanRevolving}, "revolving"},
+		{PaymentPlan{Kind: PlanBonus, FirstPayment: "2026-07"}, "bonus payment from 2026-07"},
+	}
+	for _, tt := range tests {
+		if got := tt.plan.Summary(); got != tt.expected {
+			t.Errorf("Summary(%+v) = %q, want %q", tt.plan, got, tt.expected)
+		}
+	}
+}
+
+func TestTransaction_Installments(t *testing.T) {
+	record := Transaction{
+		Date:     "2025-12-31",
+		Payee:    "家電店",
+		Amount:   Yen(-10000),
+		ImportID: "smbc_card:-10000000:2025-12-31:1",
+		Plan:     PaymentPlan{Kind: PlanInstallments, Count: 3, FirstPayment: "2026-02"},
+	}
+
+	var got [][3]string
+	for _, payment := range record.Installments() {
+		got = append(got, [3]string{payment.Date, payment.Amount.String(), payment.Memo})
+	}
+	expected := [][3]string{
+		{"2026-02-28", "-3334", "installment 1/3"}, // Remainder on the first payment, day clamped
+		{"2026-03-31", "-3333", "installment 2/3"},
+		{"2026-04-30", "-3333", "installment 3/3"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Installments() = %v, want %v", got, expected)
+	}
+
+	ids := map[string]bool{}
+	for _, payment := range record.Installments() {
+		if len(payment.ImportID) > maxImportIDLength || ids[payment.ImportID] {
+			t.Errorf("ImportID %q is too long or not unique", payment.ImportID)
+		}
+		ids[payment.ImportID] = true
+	}
+
+	// Without a payment month, payments start the month after the purchase
+	record.Plan.FirstPayment = ""
+	if first := record.Installments()[0]; first.Date != "2026-01-31" {
+		t.Errorf("first payment date = %q, want %q", first.Date, "2026-01-31")
+	}
+
+	// Other plans are left alone
+	record.Plan = PaymentPlan{Kind: PlanRevolving}
+	if payments := record.Installments(); len(payments) != 1 || payments[0].Amount != record.Amount {
+		t.Errorf("Installments() of a revolving purchase = %v", payments)
+	}
+}
@@ -0,0 +1,98 @@
+package parsers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Money is a fixed-point amount in milliunits (1/1000 of the currency unit),
+// the same resolution the YNAB API uses
+type Money struct {
+	Milliunits int64
+	Currency   string // ISO 4217 code, e.g. JPY or USD
+}
+
+// Yen returns a whole-yen amount
+func Yen(amount int64) Money {
+	return Money{Milliunits: amount * 1000, Currency: "JPY"}
+}
+
+// ParseMoney parses amounts such as "1,234", "-500", "12.50" or "＋1,000".
+// Anything that is not a number is an error rather than zero.
+func ParseMoney(value, currency string) (Money, error) {
+	str := strings.TrimSpace(value)
+	str = strings.NewReplacer(",", "", "，", "", "＋", "+", "－", "-", "−", "-", "¥", "", "\\", "", "円", "").Replace(str)
+
+	negative := false
+	if strings.HasPrefix(str, "-") || strings.HasPrefix(str, "+") {
+		negative = str[0] == '-'
+		str = str[1:]
+	}
+
+	intPart, fracPart, hasDot := strings.Cut(str, ".")
+	if intPart == "" || (hasDot && fracPart == "") {
+		return Money{}, fmt.Errorf("invalid amount %q", value)
+	}
+	if len(fracPart) > 3 {
+		return Money{}, fmt.Errorf("invalid amount %q (more than 3 decimal places)", value)
+	}
+	for _, r := range intPart + fracPart {
+		if r < '0' || r > '9' {
+			return Money{}, fmt.Errorf("invalid amount %q", value)
+		}
+	}
+	fracPart += strings.Repeat("0", 3-len(fracPart))
+
+	milliunits, err := strconv.ParseInt(intPart+fracPart, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("invalid amount %q", value)
+	}
+	if negative {
+		milliunits = -milliunits
+	}
+	return Money{Milliunits: milliunits, Currency: currency}, nil
+}
+
+// parseOutflowInflow handles exports with separate withdrawal and deposit
+// columns: the outflow (negated) wins when present, otherwise the inflow
+func parseOutflowInflow(outflow, inflow, currency string) (Money, error) {
+	if outflow != "" {
+		amount, err := ParseMoney(outflow, currency)
+		return amount.Neg(), err
+	}
+	return ParseMoney(inflow, currency)
+}
+
+// parseBalance parses an optional running balance column. Balances are
+// informational, so an empty or unreadable cell yields the zero Money
+// (no balance) instead of skipping the transaction.
+func parseBalance(value, currency string) Money {
+	balance, err := ParseMoney(value, currency)
+	if err != nil {
+		return Money{}
+	}
+	return balance
+}
+
+// Neg returns the amount with its sign flipped (outflows are negative)
+func (m Money) Neg() Money {
+	return Money{Milliunits: -m.Milliunits, Currency: m.Currency}
+}
+
+// String formats the amount without trailing zeros, e.g. "-1234" or "12.5"
+func (m Money) String() string {
+	sign := ""
+	milliunits := m.Milliunits
+	if milliunits < 0 {
+		sign = "-"
+		milliunits = -milliunits
+	}
+
+	units := strconv.FormatInt(milliunits/1000, 10)
+	frac := strings.TrimRight(fmt.Sprintf("%03d", milliunits%1000), "0")
+	if frac == "" {
+		return sign + units
+	}
+	return sign + units + "." + frac
+}
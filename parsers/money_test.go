@@ -0,0 +1,112 @@
+package parsers
+
+import (
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int64 // Milliunits
+		wantErr  bool
+	}{
+		{"positive number", "1000", 1000000, false},
+		{"negative number", "-500", -500000, false},
+		{"zero", "0", 0, false},
+		{"number with comma", "1,000", 1000000, false},
+		{"number with multiple commas", "1,000,000", 1000000000, false},
+		{"explicit plus", "+314", 314000, false},
+		{"full-width sign", "－1,234", -1234000, false},
+		{"yen sign", "\\14,173", 14173000, false},
+		{"decimal number", "1234.56", 1234560, false}, // No longer truncated
+		{"negative decimal", "-789.99", -789990, false},
+		{"three decimals", "1.234", 1234, false},
+		{"large number", "999999999", 999999999000, false},
+		{"too many decimals", "1.2345", 0, true},
+		{"invalid input", "abc", 0, true}, // No longer silently 0
+		{"empty string", "", 0, true},
+		{"dash placeholder", "-", 0, true},
+		{"trailing dot", "12.", 0, true},
+		{"embedded text", "12a3", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMoney(tt.input, "JPY")
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseMoney(%q) expected error, got %v", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseMoney(%q) unexpected error: %v", tt.input, err)
+			}
+			if got.Milliunits != tt.expected || got.Currency != "JPY" {
+				t.Errorf("ParseMoney(%q) = %+v, want %d JPY", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseOutflowInflow(t *testing.T) {
+	tests := []struct {
+		name     string
+		outflow  string
+		inflow   string
+		expected string
+		wantErr  bool
+	}{
+		{"deposit only", "", "5000", "5000", false},
+		{"withdrawal only", "3,000", "", "-3000", false},
+		{"both (withdrawal preferred)", "1000", "2000", "-1000", false},
+		{"neither", "", "", "", true},
+		{"invalid withdrawal", "abc", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseOutflowInflow(tt.outflow, tt.inflow, "JPY")
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseOutflowInflow(%q, %q) expected error, got %v", tt.outflow, tt.inflow, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseOutflowInflow(%q, %q) unexpected error: %v", tt.outflow, tt.inflow, err)
+			}
+			if got.String() != tt.expected {
+				t.Errorf("parseOutflowInflow(%q, %q) = %s, want %s", tt.outflow, tt.inflow, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMoney_String(t *testing.T) {
+	tests := []struct {
+		money    Money
+		expected string
+	}{
+		{Yen(1000), "1000"},
+		{Yen(-500), "-500"},
+		{Yen(0), "0"},
+		{Money{Milliunits: 12500, Currency: "USD"}, "12.5"},
+		{Money{Milliunits: -990, Currency: "USD"}, "-0.99"},
+		{Money{Milliunits: 1234, Currency: "USD"}, "1.234"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.String(); got != tt.expected {
+			t.Errorf("%+v.String() = %q, want %q", tt.money, got, tt.expected)
+		}
+	}
+}
+
+func TestMoney_Neg(t *testing.T) {
+	got := Money{Milliunits: 12340, Currency: "USD"}.Neg()
+	if got.Milliunits != -12340 || got.Currency != "USD" {
+		t.Errorf("Neg() = %+v, want -12340 USD", got)
+	}
+}
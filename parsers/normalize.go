@@ -0,0 +1,99 @@
+package parsers
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/width"
+)
+
+// Normalization policies for payees and memos. All but NormalizeOff apply
+// NFKC (Ｓｕｉｃａ -> Suica) and collapse whitespace; they differ in the
+// width of katakana.
+const (
+	NormalizeFull = "full" // ｿｳ ﾀｸﾍｲ -> ソウ タクヘイ
+	NormalizeHalf = "half" // ソウ タクヘイ -> ｿｳ ﾀｸﾍｲ
+	NormalizeKeep = "keep" // Katakana as exported
+	NormalizeOff  = "off"  // Payees and memos as exported
+)
+
+// NormalizePolicies lists the policies Normalize accepts
+var NormalizePolicies = []string{NormalizeFull, NormalizeHalf, NormalizeKeep, NormalizeOff}
+
+// CheckNormalizePolicy reports an error for unknown policies
+func CheckNormalizePolicy(policy string) error {
+	for _, known := range NormalizePolicies {
+		if policy == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown normalization %q (want one of %s)", policy, strings.Join(NormalizePolicies, ", "))
+}
+
+// Normalize rewrites text so the same payee reads the same every month
+func Normalize(text, policy string) string {
+	switch policy {
+	case NormalizeOff:
+		return text
+	case NormalizeKeep:
+		text = nfkcExceptHalfWidthKana(text)
+	default:
+		text = norm.NFKC.String(text)
+	}
+	if policy == NormalizeHalf {
+		text = narrowKatakana(text)
+	}
+	// Fields also splits on the ideographic space
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// NormalizeRecords normalizes the payee and memo of every record
+func NormalizeRecords(records []Transaction, policy string) {
+	for i := range records {
+		records[i].Payee = Normalize(records[i].Payee, policy)
+		records[i].Memo = Normalize(records[i].Memo, policy)
+	}
+}
+
+func isHalfWidthKana(r rune) bool {
+	return r >= 0xFF61 && r <= 0xFF9F
+}
+
+// nfkcExceptHalfWidthKana applies NFKC to everything between runs of
+// half-width katakana
+func nfkcExceptHalfWidthKana(text string) string {
+	var b, run strings.Builder
+	kana := false
+	flush := func() {
+		if kana {
+			b.WriteString(run.String())
+		} else {
+			b.WriteString(norm.NFKC.String(run.String()))
+		}
+		run.Reset()
+	}
+	for _, r := range text {
+		if isHalfWidthKana(r) != kana {
+			flush()
+			kana = !kana
+		}
+		run.WriteRune(r)
+	}
+	flush()
+	return b.String()
+}
+
+// narrowKatakana turns full-width katakana into half-width, splitting off
+// voiced sound marks (ガ -> ｶﾞ). Hiragana has no half-width form.
+func narrowKatakana(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if r >= 'ァ' && r <= 'ー' {
+			b.WriteString(width.Narrow.String(norm.NFD.String(string(r))))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
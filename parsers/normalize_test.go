@@ -0,0 +1,94 @@
+package parsers
+
+import (
+	"path/filepath"
+	"testing"
+
+	"cppcho.com/ynab_import/pdftext"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		text     string
+		policy   string
+		expected string
+	}{
+		{"ｿｳ ﾀｸﾍｲ", NormalizeFull, "ソウ タクヘイ"},
+		{"ｿｳ ﾀｸﾍｲ", NormalizeHalf, "ｿｳ ﾀｸﾍｲ"},
+		{"ｿｳ ﾀｸﾍｲ", NormalizeKeep, "ｿｳ ﾀｸﾍｲ"},
+		{"ｿｳ ﾀｸﾍｲ", NormalizeOff, "ｿｳ ﾀｸﾍｲ"},
+		{"モバイルＳｕｉｃａ", NormalizeFull, "モバイルSuica"},
+		{"モバイルＳｕｉｃａ", NormalizeHalf, "ﾓﾊﾞｲﾙSuica"},
+		{"ｱﾏｿﾞﾝ　ＡＭＡＺＯＮ", NormalizeKeep, "ｱﾏｿﾞﾝ AMAZON"},
+		{"ガソリン　スタンド", NormalizeHalf, "ｶﾞｿﾘﾝ ｽﾀﾝﾄﾞ"},
+		{"がっこう", NormalizeHalf, "がっこう"}, // Hiragana has no half-width form
+		{"  セブン－イレブン 　 調布店 ", NormalizeFull, "セブン-イレブン 調布店"},
+		{"  セブン　 調布店 ", NormalizeOff, "  セブン　 調布店 "},
+		{"", NormalizeFull, ""},
+	}
+
+	for _, tt := range tests {
+		if got := Normalize(tt.text, tt.policy); got != tt.expected {
+			t.Errorf("Normalize(%q, %s) = %q, want %q", tt.text, tt.policy, got, tt.expected)
+		}
+	}
+}
+
+func TestNormalizeRecords_Shinsei(t *testing.T) {
+	result := parseFixture(t, Shinsei{}, "testdata/shinsei_valid.csv")
+
+	memos := map[string]string{}
+	for _, policy := range []string{NormalizeFull, NormalizeHalf} {
+		records := append([]Transaction(nil), result.ValidRecords...)
+		NormalizeRecords(records, policy)
+		memos[policy] = records[4].Memo
+	}
+	if result.ValidRecords[4].Memo != "振込・振替:ｿｳ ﾀｸﾍｲ" {
+		t.Fatalf("fixture memo = %q, want the half-width transfer", result.ValidRecords[4].Memo)
+	}
+	if memos[NormalizeFull] != "振込・振替:ソウ タクヘイ" {
+		t.Errorf("full memo = %q", memos[NormalizeFull])
+	}
+	if memos[NormalizeHalf] != "振込･振替:ｿｳ ﾀｸﾍｲ" {
+		t.Errorf("half memo = %q", memos[NormalizeHalf])
+	}
+}
+
+func TestNormalizeRecords_Suica(t *testing.T) {
+	text, err := pdftext.Default.Extract(suicaFixture)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	result, err := Suica{}.ParseFile(filepath.Base(suicaFixture), text)
+	if err != nil || result == nil {
+		t.Fatalf("ParseFile() = %v, %v", result, err)
+	}
+
+	// The parser already writes normalized text, so full is a no-op
+	records := append([]Transaction(nil), result.ValidRecords...)
+	NormalizeRecords(records, NormalizeFull)
+	for i := range records {
+		if records[i] != result.ValidRecords[i] {
+			t.Errorf("Record[%d] = %+v, want %+v", i, records[i], result.ValidRecords[i])
+		}
+	}
+
+	NormalizeRecords(records, NormalizeHalf)
+	if records[2].Payee != "ﾁｬｰｼﾞ" || records[2].Memo != "ｵｰﾄﾁｬｰｼﾞ" {
+		t.Errorf("half Record[2] = %q, %q, want %q, %q", records[2].Payee, records[2].Memo, "ﾁｬｰｼﾞ", "ｵｰﾄﾁｬｰｼﾞ")
+	}
+	if records[0].Memo != "京王橋本 -> 調布" {
+		t.Errorf("half Record[0].Memo = %q, want kanji unchanged", records[0].Memo)
+	}
+}
+
+func TestCheckNormalizePolicy(t *testing.T) {
+	for _, policy := range NormalizePolicies {
+		if err := CheckNormalizePolicy(policy); err != nil {
+			t.Errorf("CheckNormalizePolicy(%q) error = %v", policy, err)
+		}
+	}
+	if err := CheckNormalizePolicy("nfc"); err == nil {
+		t.Error("CheckNormalizePolicy() expected error for an unknown policy")
+	}
+}
@@ -0,0 +1,141 @@
+// Package parsers converts bank and credit card exports into transactions
+package parsers
+
+import (
+	"time"
+)
+
+// Transaction is one row of an export in YNAB's terms
+type Transaction struct {
+	Date     string // YYYY-MM-DD
+	Payee    string
+	Memo     string
+	Amount   Money  // Positive for inflows, negative for outflows
+	ImportID string // Set by AssignImportIDs after parsing
+
+	CategoryID string // YNAB category ID, set by rules
+
+	// Overseas transactions: the amount in the original currency and the
+	// conversion rate to yen as printed in the export (e.g. USD 12.34 @ 151.2)
+	ForeignAmount Money
+	FXRate        string
+
+	// Running balance after this transaction, for exports that have one
+	Balance Money
+
+	// Account key for exports that mix several accounts, e.g. "family" for
+	// the family card rows of an SMBC Card export. Empty for a single account.
+	Account string
+
+	// Payment plan of a credit card purchase, for exports that have one
+	Plan PaymentPlan
+}
+
+// Description is the payee, or the memo for sources that only have a memo
+func (r Transaction) Description() string {
+	if r.Payee != "" {
+		return r.Payee
+	}
+	return r.Memo
+}
+
+// HasBalance reports whether the export provided a running balance for the transaction
+func (r Transaction) HasBalance() bool {
+	return r.Balance.Currency != ""
+}
+
+// HasForeignAmount reports whether the transaction carries an original currency amount
+func (r Transaction) HasForeignAmount() bool {
+	return r.ForeignAmount.Currency != ""
+}
+
+// ForeignSummary describes the original currency amount, e.g. "USD 12.34 @ 151.2"
+func (r Transaction) ForeignSummary() string {
+	if !r.HasForeignAmount() {
+		return ""
+	}
+	amount := r.ForeignAmount
+	if amount.Milliunits < 0 {
+		amount = amount.Neg() // The sign is already on the yen amount
+	}
+	summary := amount.Currency + " " + amount.String()
+	if r.FXRate != "" {
+		summary += " @ " + r.FXRate
+	}
+	return summary
+}
+
+// FullMemo is the memo with the foreign currency summary appended
+func (r Transaction) FullMemo() string {
+	summary := r.ForeignSummary()
+	if summary == "" {
+		return r.Memo
+	}
+	if r.Memo == "" {
+		return summary
+	}
+	return r.Memo + " (" + summary + ")"
+}
+
+type ParseResult struct {
+	ValidRecords []Transaction
+	SkippedRows  []SkippedRow
+}
+
+type SkippedRow struct {
+	RowNumber int
+	RawData   []string
+	Reason    string
+}
+
+// Source is the parser that produced a ParseResult, CSV or PDF. Sinks only
+// need its name (and optionally its AccountType).
+type Source interface {
+	Name() string
+}
+
+// Parser converts the raw CSV rows of one export format. Detect scores how
+// certain the parser is that the first rows (at most DetectRows) are in its
+// format. The HeaderRows rows before the first transaction are skipped and
+// ParseRow is called for each following row; it returns nil, nil for rows
+// that are not transactions and an error for rows that should be reported
+// as skipped.
+type Parser interface {
+	Name() string
+	Detect(head [][]string) Detection
+	HeaderRows() int
+	ParseRow(row []string) (*Transaction, error)
+}
+
+const (
+	AccountTypeBank       = "bank"
+	AccountTypeCreditCard = "credit_card"
+)
+
+// AccountTyper is implemented by parsers to say whether they read a bank
+// account or a credit card statement (used by statement formats like OFX)
+type AccountTyper interface {
+	AccountType() string
+}
+
+// AccountTypeOf defaults to a bank account for parsers that don't say
+func AccountTypeOf(parser Source) string {
+	if typed, ok := parser.(AccountTyper); ok && typed.AccountType() != "" {
+		return typed.AccountType()
+	}
+	return AccountTypeBank
+}
+
+// Builtin returns the built-in parsers in detection order
+func Builtin() []Parser {
+	return []Parser{Smbc{}, Rakuten{}, Epos{}, View{}, Saison{}, RakutenCard{}, Sbi{}, SmbcCard{}, SmbcCard2{}, Shinsei{}, PayPay{}}
+}
+
+// 2006-01-02T15:04:05
+func convertDate(fromLayout, toLayout, value string) (string, error) {
+	date, err := time.Parse(fromLayout, value)
+	if err != nil {
+		return "", err
+	}
+	return date.Format(toLayout), nil
+}
@@ -0,0 +1,114 @@
+package parsers
+
+import (
+	"testing"
+)
+
+func TestConvertDate(t *testing.T) {
+	tests := []struct {
+		name       string
+		fromLayout string
+		toLayout   string
+		value      string
+		expected   string
+		wantErr    bool
+	}{
+		{
+			name:       "standard format with single digits",
+			fromLayout: "2006/1/2",
+			toLayout:   "2006-01-02",
+			value:      "2024/1/15",
+			expected:   "2024-01-15",
+			wantErr:    false,
+		},
+		{
+			name:       "compact format",
+			fromLayout: "20060102",
+			toLayout:   "2006-01-02",
+			value:      "20240115",
+			expected:   "2024-01-15",
+			wantErr:    false,
+		},
+		{
+			name:       "Japanese format",
+			fromLayout: "2006年01月02日",
+			toLayout:   "2006-01-02",
+			value:      "2024年01月15日",
+			expected:   "2024-01-15",
+			wantErr:    false,
+		},
+		{
+			name:       "format with padded digits",
+			fromLayout: "2006/01/02",
+			toLayout:   "2006-01-02",
+			value:      "2024/01/15",
+			expected:   "2024-01-15",
+			wantErr:    false,
+		},
+		{
+			name:       "leap year date",
+			fromLayout: "2006/1/2",
+			toLayout:   "2006-01-02",
+			value:      "2024/2/29",
+			expected:   "2024-02-29",
+			wantErr:    false,
+		},
+		{
+			name:       "invalid date format",
+			fromLayout: "2006-01-02",
+			toLayout:   "2006-01-02",
+			value:      "invalid",
+			expected:   "",
+			wantErr:    true,
+		},
+		{
+			name:       "mismatched layout",
+			fromLayout: "2006/01/02",
+			toLayout:   "2006-01-02",
+			value:      "20240115", // compact format doesn't match
+			expected:   "",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := convertDate(tt.fromLayout, tt.toLayout, tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("convertDate(%q, %q, %q) expected error, got nil", tt.fromLayout, tt.toLayout, tt.value)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("convertDate(%q, %q, %q) unexpected error: %v", tt.fromLayout, tt.toLayout, tt.value, err)
+				}
+				if got != tt.expected {
+					t.Errorf("convertDate(%q, %q, %q) = %q, want %q", tt.fromLayout, tt.toLayout, tt.value, got, tt.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestTransaction_FullMemo(t *testing.T) {
+	usd := Money{Milliunits: -12340, Currency: "USD"}
+
+	tests := []struct {
+		name     string
+		record   Transaction
+		expected string
+	}{
+		{"no foreign amount", Transaction{Memo: "Memo"}, "Memo"},
+		{"foreign amount only", Transaction{ForeignAmount: usd, FXRate: "151.2"}, "USD 12.34 @ 151.2"},
+		{"memo and foreign amount", Transaction{Memo: "US", ForeignAmount: usd, FXRate: "151.2"}, "US (USD 12.34 @ 151.2)"},
+		{"no rate", Transaction{ForeignAmount: usd}, "USD 12.34"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.record.FullMemo(); got != tt.expected {
+				t.Errorf("FullMemo() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package parsers
+
+import (
+	"strings"
+)
+
+type PayPay struct{}
+
+func (p PayPay) Name() string {
+	return "paypay"
+}
+
+func (p PayPay) Detect(records [][]string) Detection {
+	// The UTF-8 BOM of PayPay exports is stripped while reading
+	return detectHeader(records, 0,
+		[]string{"取引日", "出金金額（円）", "入金金額（円）", "海外出金金額", "通貨", "変換レート（円）", "利用国", "取引内容", "取引先", "取引方法", "支払い区分", "利用者", "取引番号"},
+	)
+}
+
+func (p PayPay) HeaderRows() int {
+	return 1 // The header
+}
+
+func (p PayPay) ParseRow(row []string) (*Transaction, error) {
+	cols := columnsOf(row)
+	dateTime, outflow, inflow, payee := cols.get(0), cols.get(1), cols.get(2), cols.get(8)
+	if cols.err != nil {
+		return nil, cols.err
+	}
+
+	// Extract date part from datetime (2025/12/27 12:00:46 -> 2025/12/27)
+	datePart := strings.Split(dateTime, " ")[0]
+	date, err := convertDate("2006/1/2", "2006-01-02", datePart)
+	if err != nil {
+		return nil, err
+	}
+
+	// Handle withdrawal (出金金額（円）) vs deposit (入金金額（円）)
+	// Withdrawals should be negative, deposits should be positive; "-" means empty
+	amount, err := parseOutflowInflow(emptyIfDash(outflow), emptyIfDash(inflow), "JPY")
+	if err != nil {
+		return nil, err
+	}
+
+	record := Transaction{
+		Date:    date,
+		Amount:  amount,
+		Payee:   payee,                          // 取引先 (merchant/counterparty)
+		Account: emptyIfDash(cols.optional(11)), // 利用者, set for family members
+	}
+
+	// Overseas payments carry 海外出金金額, 通貨, 変換レート（円） and 利用国
+	if foreign, currency := emptyIfDash(cols.optional(3)), emptyIfDash(cols.optional(4)); foreign != "" && currency != "" {
+		foreignAmount, err := ParseMoney(foreign, currency)
+		if err != nil {
+			return nil, err
+		}
+		record.ForeignAmount = foreignAmount.Neg()
+		record.FXRate = emptyIfDash(cols.optional(5))
+		record.Memo = emptyIfDash(cols.optional(6))
+	}
+
+	return &record, nil
+}
+
+func emptyIfDash(value string) string {
+	if value == "-" {
+		return ""
+	}
+	return value
+}
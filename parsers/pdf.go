@@ -0,0 +1,81 @@
+package parsers
+
+import (
+	"fmt"
+	"sort"
+)
+
+// PDFParser reads statements that are only available as PDF (older months
+// of card statements, transit IC histories). It gets the text of the whole
+// file from a pdftext.Extractor, one printed row per line. Like Parser,
+// Parse returns nil, nil when the text is not in its format.
+type PDFParser interface {
+	Name() string
+	Detect(text string) Detection
+	Parse(text string) (*ParseResult, error)
+}
+
+// FileNameParser is implemented by PDF parsers that also read the file name,
+// e.g. for an issue date the statement itself doesn't print
+type FileNameParser interface {
+	ParseFile(fileName, text string) (*ParseResult, error)
+}
+
+// PDFCandidate is a PDF parser that recognized the text
+type PDFCandidate struct {
+	Parser PDFParser
+	Detection
+}
+
+// BuiltinPDF returns the built-in PDF parsers in detection order
+func BuiltinPDF() []PDFParser {
+	return []PDFParser{Suica{}}
+}
+
+// RankPDF returns the parsers of list that recognize text, most certain
+// first, like Rank does for CSV rows
+func RankPDF(list []PDFParser, text string) []PDFCandidate {
+	var candidates []PDFCandidate
+	for _, parser := range list {
+		if detection := SafeDetectPDF(parser, text); detection.Score > ScoreNone {
+			candidates = append(candidates, PDFCandidate{Parser: parser, Detection: detection})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	return candidates
+}
+
+// ParsePDF runs parser on the text of fileName, recovering panics, and
+// assigns import IDs like Parse
+func ParsePDF(parser PDFParser, fileName, text string) (parsed *ParseResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			parsed, err = nil, fmt.Errorf("parser %s panicked: %v", parser.Name(), r)
+		}
+	}()
+
+	if withName, ok := parser.(FileNameParser); ok {
+		parsed, err = withName.ParseFile(fileName, text)
+	} else {
+		parsed, err = parser.Parse(text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parser %s failed: %w", parser.Name(), err)
+	}
+	if parsed != nil {
+		AssignImportIDs(parser.Name(), parsed.ValidRecords)
+	}
+	return parsed, nil
+}
+
+// SafeDetectPDF runs parser.Detect, turning a panic into a rejection
+func SafeDetectPDF(parser PDFParser, text string) (detection Detection) {
+	defer func() {
+		if r := recover(); r != nil {
+			detection = rejected("detection panicked: %v", r)
+		}
+	}()
+	return parser.Detect(text)
+}
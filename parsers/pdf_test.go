@@ -0,0 +1,64 @@
+package parsers
+
+import (
+	"strings"
+	"testing"
+)
+
+// statementPDF is a PDF parser that wants the file name
+type statementPDF struct{ score int }
+
+func (p statementPDF) Name() string { return "statement" }
+
+func (p statementPDF) Detect(text string) Detection {
+	if !strings.Contains(text, "ご利用明細") {
+		return rejected("no statement heading")
+	}
+	return matched(p.score)
+}
+
+func (p statementPDF) Parse(text string) (*ParseResult, error) {
+	return p.ParseFile("", text)
+}
+
+func (p statementPDF) ParseFile(fileName, text string) (*ParseResult, error) {
+	if strings.Contains(text, "panic") {
+		panic("malformed statement")
+	}
+	return &ParseResult{ValidRecords: []Transaction{{Date: "2025-12-01", Payee: fileName, Amount: Yen(-100)}}}, nil
+}
+
+func TestRankPDF(t *testing.T) {
+	list := []PDFParser{Suica{}, statementPDF{score: ScoreWeak}}
+
+	if candidates := RankPDF(list, "請求書"); len(candidates) != 0 {
+		t.Errorf("RankPDF() = %v, want no candidates", candidates)
+	}
+
+	candidates := RankPDF(list, "モバイルＳｕｉｃａ 残高ご利用明細")
+	if len(candidates) != 2 || candidates[0].Parser.Name() != "suica" || candidates[1].Parser.Name() != "statement" {
+		t.Fatalf("RankPDF() = %v, want suica then statement", candidates)
+	}
+	if candidates[0].Score != ScoreHeader || candidates[1].Score != ScoreWeak {
+		t.Errorf("scores = %d, %d, want %d, %d", candidates[0].Score, candidates[1].Score, ScoreHeader, ScoreWeak)
+	}
+}
+
+func TestParsePDF(t *testing.T) {
+	parsed, err := ParsePDF(statementPDF{}, "statement_202512.pdf", "ご利用明細")
+	if err != nil || parsed == nil || len(parsed.ValidRecords) != 1 {
+		t.Fatalf("ParsePDF() = %v, %v", parsed, err)
+	}
+	record := parsed.ValidRecords[0]
+	if record.Payee != "statement_202512.pdf" {
+		t.Errorf("Payee = %q, want the file name passed to ParseFile", record.Payee)
+	}
+	if record.ImportID == "" {
+		t.Error("ParsePDF() should assign import IDs")
+	}
+
+	_, err = ParsePDF(statementPDF{}, "statement.pdf", "ご利用明細 panic")
+	if err == nil || !strings.Contains(err.Error(), "parser statement panicked") {
+		t.Errorf("ParsePDF() error = %v, want a recovered panic", err)
+	}
+}
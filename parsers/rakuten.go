@@ -0,0 +1,41 @@
+package parsers
+
+type Rakuten struct{}
+
+func (p Rakuten) Name() string {
+	return "rakuten"
+}
+
+func (p Rakuten) AccountType() string {
+	return AccountTypeBank
+}
+
+func (p Rakuten) Detect(records [][]string) Detection {
+	return detectHeader(records, 0, []string{"取引日", "入出金(円)", "取引後残高(円)", "入出金内容"})
+}
+
+func (p Rakuten) HeaderRows() int {
+	return 1 // The header
+}
+
+func (p Rakuten) ParseRow(row []string) (*Transaction, error) {
+	cols := columnsOf(row)
+	dateCell, amountCell, payee := cols.get(0), cols.get(1), cols.get(3)
+	if cols.err != nil {
+		return nil, cols.err
+	}
+	date, err := convertDate("20060102", "2006-01-02", dateCell)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := ParseMoney(amountCell, "JPY")
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{
+		Date:    date,
+		Amount:  amount,
+		Balance: parseBalance(cols.optional(2), "JPY"),
+		Payee:   payee,
+	}, nil
+}
@@ -0,0 +1,51 @@
+package parsers
+
+type RakutenCard struct{}
+
+func (p RakutenCard) Name() string {
+	return "rakuten_card"
+}
+
+func (p RakutenCard) AccountType() string {
+	return AccountTypeCreditCard
+}
+
+func (p RakutenCard) Detect(records [][]string) Detection {
+	// Only the last header cell is checked, the others have changed over time
+	if len(records) == 0 {
+		return rejected("no rows")
+	}
+	if len(records[0]) != 10 {
+		return rejected("header has %d columns, want 10", len(records[0]))
+	}
+	if records[0][9] != "新規サイン" {
+		return rejected("row 1 column 10 is %q, want %q", records[0][9], "新規サイン")
+	}
+	return matched(ScoreWeak)
+}
+
+func (p RakutenCard) HeaderRows() int {
+	return 1 // The header
+}
+
+func (p RakutenCard) ParseRow(row []string) (*Transaction, error) {
+	cols := columnsOf(row)
+	dateCell, payee, amountCell := cols.get(0), cols.get(1), cols.get(6)
+	if cols.err != nil {
+		return nil, cols.err
+	}
+	date, err := convertDate("2006/01/02", "2006-01-02", dateCell)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := ParseMoney(amountCell, "JPY")
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{
+		Date:   date,
+		Amount: amount.Neg(),
+		Payee:  payee,
+		Plan:   parsePaymentPlan(cols.optional(3)), // 支払方法
+	}, nil
+}
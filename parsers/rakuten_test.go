@@ -1,7 +1,9 @@
-package main
+package parsers
 
 import (
 	"testing"
+
+	"cppcho.com/ynab_import/encoding"
 )
 
 func TestRakuten_Name(t *testing.T) {
@@ -12,13 +14,13 @@ func TestRakuten_Name(t *testing.T) {
 }
 
 func TestRakuten_Parse_ValidCSV(t *testing.T) {
-	records, err := readCsvToRawRecords("testdata/parsers/rakuten_valid.csv")
+	records, err := encoding.ReadCSVFile("testdata/rakuten_valid.csv")
 	if err != nil {
-		t.Fatalf("readCsvToRawRecords() error = %v", err)
+		t.Fatalf("encoding.ReadCSVFile() error = %v", err)
 	}
 
 	parser := Rakuten{}
-	result, err := parser.Parse(records)
+	result, err := Parse(parser, records)
 	if err != nil {
 		t.Fatalf("Parse() unexpected error: %v", err)
 	}
@@ -34,24 +36,24 @@ func TestRakuten_Parse_ValidCSV(t *testing.T) {
 
 	// Verify first record
 	if len(result.ValidRecords) > 0 {
-		if result.ValidRecords[0].date != "2025-11-27" {
-			t.Errorf("Record[0].date = %q, want %q", result.ValidRecords[0].date, "2025-11-27")
+		if result.ValidRecords[0].Date != "2025-11-27" {
+			t.Errorf("Record[0].Date = %q, want %q", result.ValidRecords[0].Date, "2025-11-27")
 		}
-		if result.ValidRecords[0].amount != "-100000" {
-			t.Errorf("Record[0].amount = %q, want %q", result.ValidRecords[0].amount, "-100000")
+		if result.ValidRecords[0].Amount.String() != "-100000" {
+			t.Errorf("Record[0].Amount = %q, want %q", result.ValidRecords[0].Amount.String(), "-100000")
 		}
 	}
 }
 
 func TestRakuten_Parse_WrongHeaders(t *testing.T) {
 	// Use SMBC CSV (different headers)
-	records, err := readCsvToRawRecords("testdata/parsers/smbc_valid.csv")
+	records, err := encoding.ReadCSVFile("testdata/smbc_valid.csv")
 	if err != nil {
-		t.Fatalf("readCsvToRawRecords() error = %v", err)
+		t.Fatalf("encoding.ReadCSVFile() error = %v", err)
 	}
 
 	parser := Rakuten{}
-	result, err := parser.Parse(records)
+	result, err := Parse(parser, records)
 	if err != nil {
 		t.Errorf("Parse() unexpected error: %v", err)
 	}
@@ -70,7 +72,7 @@ func TestRakuten_Parse_DateConversion(t *testing.T) {
 		{"20250105", "-1000", "50000", "Test Payment"},
 	}
 
-	result, err := parser.Parse(mockRecords)
+	result, err := Parse(parser, mockRecords)
 	if err != nil {
 		t.Fatalf("Parse() unexpected error: %v", err)
 	}
@@ -79,8 +81,8 @@ func TestRakuten_Parse_DateConversion(t *testing.T) {
 	}
 
 	if len(result.ValidRecords) > 0 {
-		if result.ValidRecords[0].date != "2025-01-05" {
-			t.Errorf("Date conversion failed: got %q, want %q", result.ValidRecords[0].date, "2025-01-05")
+		if result.ValidRecords[0].Date != "2025-01-05" {
+			t.Errorf("Date conversion failed: got %q, want %q", result.ValidRecords[0].Date, "2025-01-05")
 		}
 	}
 }
@@ -104,7 +106,7 @@ func TestRakuten_Parse_AmountHandling(t *testing.T) {
 				{"20250101", tt.amount, "100000", "Test"},
 			}
 
-			result, err := parser.Parse(mockRecords)
+			result, err := Parse(parser, mockRecords)
 			if err != nil {
 				t.Fatalf("Parse() unexpected error: %v", err)
 			}
@@ -112,8 +114,8 @@ func TestRakuten_Parse_AmountHandling(t *testing.T) {
 				t.Fatal("Parse() returned nil or empty")
 			}
 
-			if result.ValidRecords[0].amount != tt.expectedAmount {
-				t.Errorf("Amount = %q, want %q", result.ValidRecords[0].amount, tt.expectedAmount)
+			if result.ValidRecords[0].Amount.String() != tt.expectedAmount {
+				t.Errorf("Amount = %q, want %q", result.ValidRecords[0].Amount.String(), tt.expectedAmount)
 			}
 		})
 	}
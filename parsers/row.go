@@ -0,0 +1,36 @@
+package parsers
+
+import (
+	"fmt"
+)
+
+// columns gives bounds-checked access to the cells of one CSV row. Rows may
+// have any length (e.g. trailing summary lines), so reading a column the row
+// doesn't have returns "" and records an error for the row to be skipped.
+type columns struct {
+	row []string
+	err error
+}
+
+func columnsOf(row []string) *columns {
+	return &columns{row: row}
+}
+
+// get returns column i (0-based); err is set when the row is too short
+func (c *columns) get(i int) string {
+	if i < len(c.row) {
+		return c.row[i]
+	}
+	if c.err == nil {
+		c.err = fmt.Errorf("row has %d column(s), want at least %d", len(c.row), i+1)
+	}
+	return ""
+}
+
+// optional returns column i, or "" when the row is too short
+func (c *columns) optional(i int) string {
+	if i < len(c.row) {
+		return c.row[i]
+	}
+	return ""
+}
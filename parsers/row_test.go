@@ -0,0 +1,91 @@
+package parsers
+
+import (
+	"testing"
+)
+
+func TestColumns(t *testing.T) {
+	cols := columnsOf([]string{"a", "b"})
+
+	if got := cols.optional(5); got != "" || cols.err != nil {
+		t.Errorf("optional(5) = %q, err %v, want empty and no error", got, cols.err)
+	}
+	if got := cols.get(1); got != "b" || cols.err != nil {
+		t.Errorf("get(1) = %q, err %v, want %q and no error", got, cols.err, "b")
+	}
+	if got := cols.get(3); got != "" || cols.err == nil {
+		t.Fatalf("get(3) = %q, err %v, want empty and an error", got, cols.err)
+	}
+	if want := "row has 2 column(s), want at least 4"; cols.err.Error() != want {
+		t.Errorf("err = %q, want %q", cols.err, want)
+	}
+
+	// The first out-of-range column is reported
+	cols.get(9)
+	if want := "row has 2 column(s), want at least 4"; cols.err.Error() != want {
+		t.Errorf("err after second get = %q, want %q", cols.err, want)
+	}
+}
+
+// Trailing summary lines are shorter than transaction rows
+func TestParse_ShortRows(t *testing.T) {
+	tests := []struct {
+		parser  Parser
+		records [][]string
+		row     int
+	}{
+		{
+			parser: Smbc{},
+			records: [][]string{
+				{"年月日", "お引出し", "お預入れ", "お取り扱い内容", "残高", "メモ", "ラベル"},
+				{"2025/12/26", "100", "", "テスト", "900", "", ""},
+				{"合計", "100"},
+			},
+			row: 3,
+		},
+		{
+			parser: RakutenCard{},
+			records: [][]string{
+				{"利用日", "利用店名・商品名", "利用者", "支払方法", "利用金額", "支払手数料", "支払総額", "当月請求額", "翌月繰越残高", "新規サイン"},
+				{"2025/12/01", "テスト", "本人", "1回払い", "1000", "0", "1000", "1000", "0", "*"},
+				{"合計", "1000"},
+			},
+			row: 3,
+		},
+		{
+			parser: SmbcCard{},
+			records: [][]string{
+				{"2025/12/5", "Test Merchant", "ご本人", "1回払い", "", "'26/01", "2230", "2230", "", "", "", "", ""},
+				{"", "", "", "", "", "2230"},
+			},
+			row: 2,
+		},
+		{
+			parser: PayPay{},
+			records: [][]string{
+				{"取引日", "出金金額（円）", "入金金額（円）", "海外出金金額", "通貨", "変換レート（円）", "利用国", "取引内容", "取引先", "取引方法", "支払い区分", "利用者", "取引番号"},
+				{"2025/1/5 12:00:00", "1000", "-", "-", "-", "-", "-", "支払い", "Valid", "PayPay残高", "-", "-", "12345"},
+				{"2025/1/6 13:00:00", "3000"},
+			},
+			row: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.parser.Name(), func(t *testing.T) {
+			result, err := Parse(tt.parser, tt.records)
+			if err != nil || result == nil {
+				t.Fatalf("Parse() = %v, %v", result, err)
+			}
+			if len(result.ValidRecords) != 1 {
+				t.Errorf("Parse() returned %d valid records, want 1", len(result.ValidRecords))
+			}
+			if len(result.SkippedRows) != 1 {
+				t.Fatalf("Parse() returned %d skipped rows, want 1", len(result.SkippedRows))
+			}
+			if got := result.SkippedRows[0]; got.RowNumber != tt.row || got.Reason == "" {
+				t.Errorf("SkippedRows[0] = %+v, want row %d with a reason", got, tt.row)
+			}
+		})
+	}
+}
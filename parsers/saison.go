@@ -0,0 +1,49 @@
+package parsers
+
+type Saison struct{}
+
+func (p Saison) Name() string {
+	return "saison"
+}
+
+func (p Saison) AccountType() string {
+	return AccountTypeCreditCard
+}
+
+func (p Saison) Detect(records [][]string) Detection {
+	if len(records) <= 4 {
+		return rejected("too few rows: want more than 4, got %d", len(records))
+	}
+	if cell, _ := cellAt(records, 0, 0); cell != "カード名称" {
+		return rejected("row 1 column 1 is %q, want %q", cell, "カード名称")
+	}
+	if cell, _ := cellAt(records, 3, 0); cell != "利用日" {
+		return rejected("row 4 column 1 is %q, want %q", cell, "利用日")
+	}
+	return matched(ScoreLayout)
+}
+
+func (p Saison) HeaderRows() int {
+	return 4 // Card and statement details above the transactions
+}
+
+func (p Saison) ParseRow(row []string) (*Transaction, error) {
+	cols := columnsOf(row)
+	dateCell, payee, amountCell := cols.get(0), cols.get(1), cols.get(5)
+	if cols.err != nil {
+		return nil, cols.err
+	}
+	date, err := convertDate("2006/01/02", "2006-01-02", dateCell)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := ParseMoney(amountCell, "JPY")
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{
+		Date:   date,
+		Amount: amount.Neg(),
+		Payee:  payee,
+	}, nil
+}
@@ -0,0 +1,41 @@
+package parsers
+
+type Sbi struct{}
+
+func (p Sbi) Name() string {
+	return "sbi"
+}
+
+func (p Sbi) AccountType() string {
+	return AccountTypeBank
+}
+
+func (p Sbi) Detect(records [][]string) Detection {
+	return detectHeader(records, 0, []string{"日付", "内容", "出金金額(円)", "入金金額(円)", "残高(円)", "メモ"})
+}
+
+func (p Sbi) HeaderRows() int {
+	return 1 // The header
+}
+
+func (p Sbi) ParseRow(row []string) (*Transaction, error) {
+	cols := columnsOf(row)
+	dateCell, memo, outflow, inflow := cols.get(0), cols.get(1), cols.get(2), cols.get(3)
+	if cols.err != nil {
+		return nil, cols.err
+	}
+	date, err := convertDate("2006/01/02", "2006-01-02", dateCell)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := parseOutflowInflow(outflow, inflow, "JPY")
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{
+		Date:    date,
+		Amount:  amount,
+		Balance: parseBalance(cols.optional(4), "JPY"),
+		Memo:    memo,
+	}, nil
+}
@@ -1,7 +1,9 @@
-package main
+package parsers
 
 import (
 	"testing"
+
+	"cppcho.com/ynab_import/encoding"
 )
 
 func TestSbi_Name(t *testing.T) {
@@ -12,13 +14,13 @@ func TestSbi_Name(t *testing.T) {
 }
 
 func TestSbi_Parse_ValidCSV(t *testing.T) {
-	records, err := readCsvToRawRecords("testdata/parsers/sbi_valid.csv")
+	records, err := encoding.ReadCSVFile("testdata/sbi_valid.csv")
 	if err != nil {
-		t.Fatalf("readCsvToRawRecords() error = %v", err)
+		t.Fatalf("encoding.ReadCSVFile() error = %v", err)
 	}
 
 	parser := Sbi{}
-	result, err := parser.Parse(records)
+	result, err := Parse(parser, records)
 	if err != nil {
 		t.Fatalf("Parse() unexpected error: %v", err)
 	}
@@ -34,33 +36,33 @@ func TestSbi_Parse_ValidCSV(t *testing.T) {
 
 	// Verify first record (withdrawal)
 	if len(result.ValidRecords) > 0 {
-		if result.ValidRecords[0].date != "2025-12-26" {
-			t.Errorf("Record[0].date = %q, want %q", result.ValidRecords[0].date, "2025-12-26")
+		if result.ValidRecords[0].Date != "2025-12-26" {
+			t.Errorf("Record[0].Date = %q, want %q", result.ValidRecords[0].Date, "2025-12-26")
 		}
 		// Withdrawal amount should be flipped
-		if result.ValidRecords[0].amount != "-91688" {
-			t.Errorf("Record[0].amount = %q, want %q (flipSign applied)", result.ValidRecords[0].amount, "-91688")
+		if result.ValidRecords[0].Amount.String() != "-91688" {
+			t.Errorf("Record[0].Amount = %q, want %q (sign flipped)", result.ValidRecords[0].Amount.String(), "-91688")
 		}
 	}
 
 	// Verify second record (deposit)
 	if len(result.ValidRecords) > 1 {
 		// Deposit amount should not be flipped
-		if result.ValidRecords[1].amount != "50000" {
-			t.Errorf("Record[1].amount = %q, want %q", result.ValidRecords[1].amount, "50000")
+		if result.ValidRecords[1].Amount.String() != "50000" {
+			t.Errorf("Record[1].Amount = %q, want %q", result.ValidRecords[1].Amount.String(), "50000")
 		}
 	}
 }
 
 func TestSbi_Parse_WrongHeaders(t *testing.T) {
 	// Use SMBC CSV (different headers)
-	records, err := readCsvToRawRecords("testdata/parsers/smbc_valid.csv")
+	records, err := encoding.ReadCSVFile("testdata/smbc_valid.csv")
 	if err != nil {
-		t.Fatalf("readCsvToRawRecords() error = %v", err)
+		t.Fatalf("encoding.ReadCSVFile() error = %v", err)
 	}
 
 	parser := Sbi{}
-	result, err := parser.Parse(records)
+	result, err := Parse(parser, records)
 	if err != nil {
 		t.Errorf("Parse() unexpected error: %v", err)
 	}
@@ -79,7 +81,7 @@ func TestSbi_Parse_DateConversion(t *testing.T) {
 		{"2025/01/05", "Test", "", "1000", "100000", "-"},
 	}
 
-	result, err := parser.Parse(mockRecords)
+	result, err := Parse(parser, mockRecords)
 	if err != nil {
 		t.Fatalf("Parse() unexpected error: %v", err)
 	}
@@ -88,8 +90,8 @@ func TestSbi_Parse_DateConversion(t *testing.T) {
 	}
 
 	if len(result.ValidRecords) > 0 {
-		if result.ValidRecords[0].date != "2025-01-05" {
-			t.Errorf("Date conversion failed: got %q, want %q", result.ValidRecords[0].date, "2025-01-05")
+		if result.ValidRecords[0].Date != "2025-01-05" {
+			t.Errorf("Date conversion failed: got %q, want %q", result.ValidRecords[0].Date, "2025-01-05")
 		}
 	}
 }
@@ -115,7 +117,7 @@ func TestSbi_Parse_AmountHandling(t *testing.T) {
 				{"2025/01/01", "Test", tt.withdrawal, tt.deposit, "100000", "-"},
 			}
 
-			result, err := parser.Parse(mockRecords)
+			result, err := Parse(parser, mockRecords)
 			if err != nil {
 				t.Fatalf("Parse() unexpected error: %v", err)
 			}
@@ -123,8 +125,8 @@ func TestSbi_Parse_AmountHandling(t *testing.T) {
 				t.Fatal("Parse() returned nil or empty")
 			}
 
-			if result.ValidRecords[0].amount != tt.expectedAmount {
-				t.Errorf("Amount = %q, want %q", result.ValidRecords[0].amount, tt.expectedAmount)
+			if result.ValidRecords[0].Amount.String() != tt.expectedAmount {
+				t.Errorf("Amount = %q, want %q", result.ValidRecords[0].Amount.String(), tt.expectedAmount)
 			}
 		})
 	}
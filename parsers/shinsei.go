@@ -0,0 +1,45 @@
+package parsers
+
+type Shinsei struct{}
+
+func (p Shinsei) Name() string {
+	return "shinsei"
+}
+
+func (p Shinsei) AccountType() string {
+	return AccountTypeBank
+}
+
+func (p Shinsei) Detect(records [][]string) Detection {
+	// Check for both quoted and unquoted header formats
+	return detectHeader(records, 0,
+		[]string{"取引日", "摘要", "出金金額", "入金金額", "残高"},
+		[]string{"\"取引日\"", "\"摘要\"", "\"出金金額\"", "\"入金金額\"", "\"残高\""},
+	)
+}
+
+func (p Shinsei) HeaderRows() int {
+	return 1 // The header
+}
+
+func (p Shinsei) ParseRow(row []string) (*Transaction, error) {
+	cols := columnsOf(row)
+	dateCell, memo, outflow, inflow := cols.get(0), cols.get(1), cols.get(2), cols.get(3)
+	if cols.err != nil {
+		return nil, cols.err
+	}
+	date, err := convertDate("2006/01/02", "2006-01-02", dateCell)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := parseOutflowInflow(outflow, inflow, "JPY")
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{
+		Date:    date,
+		Amount:  amount,
+		Memo:    memo,
+		Balance: parseBalance(cols.optional(4), "JPY"),
+	}, nil
+}
@@ -1,7 +1,9 @@
-package main
+package parsers
 
 import (
 	"testing"
+
+	"cppcho.com/ynab_import/encoding"
 )
 
 func TestShinsei_Name(t *testing.T) {
@@ -12,13 +14,13 @@ func TestShinsei_Name(t *testing.T) {
 }
 
 func TestShinsei_Parse_ValidCSV(t *testing.T) {
-	records, err := readCsvToRawRecords("testdata/parsers/shinsei_valid.csv")
+	records, err := encoding.ReadCSVFile("testdata/shinsei_valid.csv")
 	if err != nil {
-		t.Fatalf("readCsvToRawRecords() error = %v", err)
+		t.Fatalf("encoding.ReadCSVFile() error = %v", err)
 	}
 
 	parser := Shinsei{}
-	result, err := parser.Parse(records)
+	result, err := Parse(parser, records)
 	if err != nil {
 		t.Fatalf("Parse() unexpected error: %v", err)
 	}
@@ -34,42 +36,42 @@ func TestShinsei_Parse_ValidCSV(t *testing.T) {
 
 	// Verify first record (withdrawal - 地方税)
 	if len(result.ValidRecords) > 0 {
-		if result.ValidRecords[0].date != "2026-01-01" {
-			t.Errorf("Record[0].date = %q, want %q", result.ValidRecords[0].date, "2026-01-01")
+		if result.ValidRecords[0].Date != "2026-01-01" {
+			t.Errorf("Record[0].Date = %q, want %q", result.ValidRecords[0].Date, "2026-01-01")
 		}
 		// Withdrawal amount should be flipped
-		if result.ValidRecords[0].amount != "-3" {
-			t.Errorf("Record[0].amount = %q, want %q (flipSign applied)", result.ValidRecords[0].amount, "-3")
+		if result.ValidRecords[0].Amount.String() != "-3" {
+			t.Errorf("Record[0].Amount = %q, want %q (sign flipped)", result.ValidRecords[0].Amount.String(), "-3")
 		}
-		if result.ValidRecords[0].memo != "地方税" {
-			t.Errorf("Record[0].memo = %q, want %q", result.ValidRecords[0].memo, "地方税")
+		if result.ValidRecords[0].Memo != "地方税" {
+			t.Errorf("Record[0].Memo = %q, want %q", result.ValidRecords[0].Memo, "地方税")
 		}
 	}
 
 	// Verify third record (deposit - 税引前利息)
 	if len(result.ValidRecords) > 2 {
-		if result.ValidRecords[2].date != "2026-01-01" {
-			t.Errorf("Record[2].date = %q, want %q", result.ValidRecords[2].date, "2026-01-01")
+		if result.ValidRecords[2].Date != "2026-01-01" {
+			t.Errorf("Record[2].Date = %q, want %q", result.ValidRecords[2].Date, "2026-01-01")
 		}
 		// Deposit amount should not be flipped
-		if result.ValidRecords[2].amount != "76" {
-			t.Errorf("Record[2].amount = %q, want %q", result.ValidRecords[2].amount, "76")
+		if result.ValidRecords[2].Amount.String() != "76" {
+			t.Errorf("Record[2].Amount = %q, want %q", result.ValidRecords[2].Amount.String(), "76")
 		}
-		if result.ValidRecords[2].memo != "税引前利息" {
-			t.Errorf("Record[2].memo = %q, want %q", result.ValidRecords[2].memo, "税引前利息")
+		if result.ValidRecords[2].Memo != "税引前利息" {
+			t.Errorf("Record[2].Memo = %q, want %q", result.ValidRecords[2].Memo, "税引前利息")
 		}
 	}
 }
 
 func TestShinsei_Parse_WrongHeaders(t *testing.T) {
 	// Use SMBC CSV (different headers)
-	records, err := readCsvToRawRecords("testdata/parsers/smbc_valid.csv")
+	records, err := encoding.ReadCSVFile("testdata/smbc_valid.csv")
 	if err != nil {
-		t.Fatalf("readCsvToRawRecords() error = %v", err)
+		t.Fatalf("encoding.ReadCSVFile() error = %v", err)
 	}
 
 	parser := Shinsei{}
-	result, err := parser.Parse(records)
+	result, err := Parse(parser, records)
 	if err != nil {
 		t.Errorf("Parse() unexpected error: %v", err)
 	}
@@ -88,7 +90,7 @@ func TestShinsei_Parse_DateConversion(t *testing.T) {
 		{"2025/01/05", "Test", "", "1000", "100000"},
 	}
 
-	result, err := parser.Parse(mockRecords)
+	result, err := Parse(parser, mockRecords)
 	if err != nil {
 		t.Fatalf("Parse() unexpected error: %v", err)
 	}
@@ -97,8 +99,8 @@ func TestShinsei_Parse_DateConversion(t *testing.T) {
 	}
 
 	if len(result.ValidRecords) > 0 {
-		if result.ValidRecords[0].date != "2025-01-05" {
-			t.Errorf("Date conversion failed: got %q, want %q", result.ValidRecords[0].date, "2025-01-05")
+		if result.ValidRecords[0].Date != "2025-01-05" {
+			t.Errorf("Date conversion failed: got %q, want %q", result.ValidRecords[0].Date, "2025-01-05")
 		}
 	}
 }
@@ -124,7 +126,7 @@ func TestShinsei_Parse_AmountHandling(t *testing.T) {
 				{"2025/01/01", "Test", tt.withdrawal, tt.deposit, "100000"},
 			}
 
-			result, err := parser.Parse(mockRecords)
+			result, err := Parse(parser, mockRecords)
 			if err != nil {
 				t.Fatalf("Parse() unexpected error: %v", err)
 			}
@@ -132,9 +134,17 @@ func TestShinsei_Parse_AmountHandling(t *testing.T) {
 				t.Fatal("Parse() returned nil or empty")
 			}
 
-			if result.ValidRecords[0].amount != tt.expectedAmount {
-				t.Errorf("Amount = %q, want %q", result.ValidRecords[0].amount, tt.expectedAmount)
+			if result.ValidRecords[0].Amount.String() != tt.expectedAmount {
+				t.Errorf("Amount = %q, want %q", result.ValidRecords[0].Amount.String(), tt.expectedAmount)
 			}
 		})
 	}
 }
+
+func TestShinsei_Balance(t *testing.T) {
+	result := parseFixture(t, Shinsei{}, "testdata/shinsei_valid.csv")
+	balance, date, ok := ClosingBalance(result.ValidRecords)
+	if !ok || balance.String() != "425289" || date != "2026-01-01" {
+		t.Errorf("ClosingBalance() = %s on %s (%v), want 425289 on 2026-01-01", balance, date, ok)
+	}
+}
@@ -0,0 +1,41 @@
+package parsers
+
+type Smbc struct{}
+
+func (p Smbc) Name() string {
+	return "smbc"
+}
+
+func (p Smbc) AccountType() string {
+	return AccountTypeBank
+}
+
+func (p Smbc) Detect(records [][]string) Detection {
+	return detectHeader(records, 0, []string{"年月日", "お引出し", "お預入れ", "お取り扱い内容", "残高", "メモ", "ラベル"})
+}
+
+func (p Smbc) HeaderRows() int {
+	return 1 // The header
+}
+
+func (p Smbc) ParseRow(row []string) (*Transaction, error) {
+	cols := columnsOf(row)
+	dateCell, outflow, inflow, payee := cols.get(0), cols.get(1), cols.get(2), cols.get(3)
+	if cols.err != nil {
+		return nil, cols.err
+	}
+	date, err := convertDate("2006/1/2", "2006-01-02", dateCell)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := parseOutflowInflow(outflow, inflow, "JPY")
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{
+		Date:    date,
+		Amount:  amount,
+		Balance: parseBalance(cols.optional(4), "JPY"),
+		Payee:   payee,
+	}, nil
+}
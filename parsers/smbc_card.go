@@ -0,0 +1,93 @@
+package parsers
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+type SmbcCard struct{}
+
+// Card holder (column 3) -> account key
+var smbcCardHolders = map[string]string{"ご本人": "self", "ご家族": "family"}
+
+func (p SmbcCard) Name() string {
+	return "smbc_card"
+}
+
+func (p SmbcCard) AccountType() string {
+	return AccountTypeCreditCard
+}
+
+func (p SmbcCard) Detect(records [][]string) Detection {
+	// There is no header, the first row is already a transaction
+	if cell, _ := cellAt(records, 0, 2); cell != "ご本人" && cell != "ご家族" {
+		return rejected("row 1 column 3 is %q, want %q or %q", cell, "ご本人", "ご家族")
+	}
+	if cell, _ := cellAt(records, 0, 5); !strings.HasPrefix(cell, "'") {
+		return rejected("row 1 column 6 is %q, want a value starting with '", cell)
+	}
+	return matched(ScoreWeak)
+}
+
+func (p SmbcCard) HeaderRows() int {
+	return 0 // No header, the first row is already a transaction
+}
+
+func (p SmbcCard) ParseRow(row []string) (*Transaction, error) {
+	cols := columnsOf(row)
+	dateCell, payee, amountCell := cols.get(0), cols.get(1), cols.get(7)
+	if cols.err != nil {
+		return nil, cols.err
+	}
+
+	date, err := convertDate("2006/1/2", "2006-01-02", dateCell)
+	if err != nil {
+		return nil, err
+	}
+
+	// Use column 7 for amount, or column 6 if column 7 is empty (international transactions)
+	if amountCell == "" {
+		amountCell = cols.optional(6)
+	}
+	amount, err := ParseMoney(amountCell, "JPY")
+	if err != nil {
+		return nil, err
+	}
+
+	record := Transaction{
+		Date:    date,
+		Amount:  amount.Neg(),
+		Payee:   payee,
+		Account: smbcCardHolders[cols.optional(2)],
+		Plan:    smbcCardPlan(cols),
+	}
+
+	// International transactions also carry the local currency amount,
+	// the currency code and the conversion rate in columns 8-10
+	if foreign, currency := cols.optional(8), cols.optional(9); foreign != "" && currency != "" {
+		if foreignAmount, err := ParseMoney(foreign, currency); err == nil {
+			record.ForeignAmount = foreignAmount.Neg()
+			record.FXRate = cols.optional(10)
+		}
+	}
+
+	return &record, nil
+}
+
+// smbcCardPlan reads the payment type (column 4), the number of payments
+// of 分割 purchases (column 5, e.g. 3 or 1/3) and the payment month
+// (column 6, e.g. '26/01)
+func smbcCardPlan(cols *columns) PaymentPlan {
+	plan := parsePaymentPlan(cols.optional(3))
+	if plan.Kind == PlanInstallments && plan.Count == 0 {
+		count := norm.NFKC.String(cols.optional(4))
+		if _, total, ok := strings.Cut(count, "/"); ok {
+			count = total
+		}
+		plan.Count, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(count), "回"))
+	}
+	plan.FirstPayment = parsePaymentMonth(cols.optional(5))
+	return plan
+}
@@ -0,0 +1,56 @@
+package parsers
+
+import (
+	"strings"
+	"time"
+)
+
+type SmbcCard2 struct{}
+
+func (p SmbcCard2) Name() string {
+	return "smbc_card2"
+}
+
+func (p SmbcCard2) AccountType() string {
+	return AccountTypeCreditCard
+}
+
+func (p SmbcCard2) Detect(records [][]string) Detection {
+	// The first row is the card holder and a masked card number
+	if cell, _ := cellAt(records, 0, 0); !strings.HasSuffix(cell, "様") {
+		return rejected("row 1 column 1 is %q, want a name ending in 様", cell)
+	}
+	if cell, _ := cellAt(records, 0, 1); !strings.HasSuffix(cell, "****") {
+		return rejected("row 1 column 2 is %q, want a card number ending in ****", cell)
+	}
+	return matched(ScoreWeak)
+}
+
+func (p SmbcCard2) HeaderRows() int {
+	return 0 // The holder row is skipped as it has no date
+}
+
+func (p SmbcCard2) ParseRow(row []string) (*Transaction, error) {
+	cols := columnsOf(row)
+	// Only rows starting with a date are transactions
+	if _, err := time.Parse("2006/1/2", cols.optional(0)); err != nil {
+		return nil, nil
+	}
+	dateCell, payee, amountCell := cols.get(0), cols.get(1), cols.get(5)
+	if cols.err != nil {
+		return nil, cols.err
+	}
+	date, err := convertDate("2006/1/2", "2006-01-02", dateCell)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := ParseMoney(amountCell, "JPY")
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{
+		Date:   date,
+		Amount: amount.Neg(),
+		Payee:  payee,
+	}, nil
+}
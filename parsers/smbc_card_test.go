@@ -1,7 +1,10 @@
-package main
+package parsers
 
 import (
+	"strings"
 	"testing"
+
+	"cppcho.com/ynab_import/encoding"
 )
 
 func TestSmbcCard_Name(t *testing.T) {
@@ -12,13 +15,13 @@ func TestSmbcCard_Name(t *testing.T) {
 }
 
 func TestSmbcCard_Parse_ValidCSV(t *testing.T) {
-	records, err := readCsvToRawRecords("testdata/parsers/smbc_card_valid.csv")
+	records, err := encoding.ReadCSVFile("testdata/smbc_card_valid.csv")
 	if err != nil {
-		t.Fatalf("readCsvToRawRecords() error = %v", err)
+		t.Fatalf("encoding.ReadCSVFile() error = %v", err)
 	}
 
 	parser := SmbcCard{}
-	result, err := parser.Parse(records)
+	result, err := Parse(parser, records)
 	if err != nil {
 		t.Fatalf("Parse() unexpected error: %v", err)
 	}
@@ -34,25 +37,60 @@ func TestSmbcCard_Parse_ValidCSV(t *testing.T) {
 
 	// Verify first record
 	if len(result.ValidRecords) > 0 {
-		if result.ValidRecords[0].date != "2025-12-23" {
-			t.Errorf("Record[0].date = %q, want %q", result.ValidRecords[0].date, "2025-12-23")
+		if result.ValidRecords[0].Date != "2025-12-23" {
+			t.Errorf("Record[0].Date = %q, want %q", result.ValidRecords[0].Date, "2025-12-23")
 		}
 		// Amount should be flipped (negative)
-		if result.ValidRecords[0].amount != "-2230" {
-			t.Errorf("Record[0].amount = %q, want %q (flipSign applied)", result.ValidRecords[0].amount, "-2230")
+		if result.ValidRecords[0].Amount.String() != "-2230" {
+			t.Errorf("Record[0].Amount = %q, want %q (sign flipped)", result.ValidRecords[0].Amount.String(), "-2230")
+		}
+	}
+
+	// Card holders become account keys
+	var accounts []string
+	for _, record := range result.ValidRecords {
+		accounts = append(accounts, record.Account)
+	}
+	if strings.Join(accounts, ",") != "self,self,family" {
+		t.Errorf("Accounts = %q, want self, self, family", accounts)
+	}
+
+	plan := result.ValidRecords[0].Plan
+	if plan.Kind != PlanLump || plan.FirstPayment != "2026-01" {
+		t.Errorf("Record[0].Plan = %+v, want a lump payment in 2026-01", plan)
+	}
+}
+
+func TestSmbcCard_Parse_Installments(t *testing.T) {
+	records := [][]string{
+		{"2025/12/23", "家電店", "ご本人", "分割", "3", "'26/01", "30000", "30000", "", "", "", "", ""},
+		{"2025/12/24", "書店", "ご本人", "リボ", "", "'26/01", "5000", "5000", "", "", "", "", ""},
+	}
+
+	result, err := Parse(SmbcCard{}, records)
+	if err != nil || result == nil {
+		t.Fatalf("Parse() = %v, %v", result, err)
+	}
+	expected := []PaymentPlan{
+		{Kind: PlanInstallments, Label: "分割", Count: 3, FirstPayment: "2026-01"},
+		{Kind: PlanRevolving, Label: "リボ", FirstPayment: "2026-01"},
+	}
+	for i, want := range expected {
+		if got := result.ValidRecords[i].Plan; got != want {
+			t.Errorf("Record[%d].Plan = %+v, want %+v", i, got, want)
 		}
 	}
 }
 
 func TestSmbcCard_Parse_WrongHeaders(t *testing.T) {
 	// Use SMBC CSV (different format)
-	records, err := readCsvToRawRecords("testdata/parsers/smbc_valid.csv")
+	records, err := encoding.ReadCSVFile("testdata/smbc_valid.csv")
 	if err != nil {
-		t.Fatalf("readCsvToRawRecords() error = %v", err)
+		t.Fatalf("encoding.ReadCSVFile() error = %v", err)
 	}
 
 	parser := SmbcCard{}
-	result, err := parser.Parse(records)
+	result, err := Parse(parser, records)
 	if err != nil {
 		t.Errorf("Parse() unexpected error: %v", err)
 	}
@@ -70,7 +108,7 @@ func TestSmbcCard_Parse_DateConversion(t *testing.T) {
 		{"2025/1/5", "Test Shop", "ご本人", "1回払い", "", "'26/01", "1000", "1000", "", "", "", "", ""},
 	}
 
-	result, err := parser.Parse(mockRecords)
+	result, err := Parse(parser, mockRecords)
 	if err != nil {
 		t.Fatalf("Parse() unexpected error: %v", err)
 	}
@@ -79,8 +117,8 @@ func TestSmbcCard_Parse_DateConversion(t *testing.T) {
 	}
 
 	if len(result.ValidRecords) > 0 {
-		if result.ValidRecords[0].date != "2025-01-05" {
-			t.Errorf("Date conversion failed: got %q, want %q", result.ValidRecords[0].date, "2025-01-05")
+		if result.ValidRecords[0].Date != "2025-01-05" {
+			t.Errorf("Date conversion failed: got %q, want %q", result.ValidRecords[0].Date, "2025-01-05")
 		}
 	}
 }
@@ -106,7 +144,7 @@ func TestSmbcCard_Parse_Validation(t *testing.T) {
 				{"2025/1/1", "Test", tt.col2, "1回払い", "", tt.col5, "1000", "1000", "", "", "", "", ""},
 			}
 
-			result, err := parser.Parse(mockRecords)
+			result, err := Parse(parser, mockRecords)
 			if err != nil {
 				t.Fatalf("Parse() unexpected error: %v", err)
 			}
@@ -133,7 +171,7 @@ func TestSmbcCard_Parse_EmptyColumn7Fallback(t *testing.T) {
 			name:           "normal transaction with col7",
 			col6:           "2230",
 			col7:           "2230",
-			expectedAmount: "-2230", // flipSign applied
+			expectedAmount: "-2230", // Sign flipped
 		},
 		{
 			name:           "international transaction - empty col7",
@@ -155,7 +193,7 @@ func TestSmbcCard_Parse_EmptyColumn7Fallback(t *testing.T) {
 				{"2025/12/5", "Test Merchant", "ご本人", "1回払い", "", "'26/01", tt.col6, tt.col7, "", "", "", "", ""},
 			}
 
-			result, err := parser.Parse(mockRecords)
+			result, err := Parse(parser, mockRecords)
 			if err != nil {
 				t.Fatalf("Parse() unexpected error: %v", err)
 			}
@@ -167,9 +205,37 @@ func TestSmbcCard_Parse_EmptyColumn7Fallback(t *testing.T) {
 				t.Fatalf("Parse() returned %d records, want 1", len(result.ValidRecords))
 			}
 
-			if result.ValidRecords[0].amount != tt.expectedAmount {
-				t.Errorf("amount = %q, want %q", result.ValidRecords[0].amount, tt.expectedAmount)
+			if result.ValidRecords[0].Amount.String() != tt.expectedAmount {
+				t.Errorf("amount = %q, want %q", result.ValidRecords[0].Amount.String(), tt.expectedAmount)
 			}
 		})
 	}
 }
+
+func TestSmbcCard_Parse_ForeignCurrency(t *testing.T) {
+	parser := SmbcCard{}
+
+	mockRecords := [][]string{
+		{"2025/12/5", "OVERSEAS SHOP", "ご本人", "1回払い", "", "'26/01", "3198", "", "21.15", "USD", "151.20", "", ""},
+		{"2025/12/6", "Domestic Shop", "ご本人", "1回払い", "", "'26/01", "1000", "1000", "", "", "", "", ""},
+	}
+
+	result, err := Parse(parser, mockRecords)
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+	if result == nil || len(result.ValidRecords) != 2 {
+		t.Fatalf("Parse() = %+v, want 2 records", result)
+	}
+
+	overseas := result.ValidRecords[0]
+	if overseas.Amount.String() != "-3198" {
+		t.Errorf("amount = %q, want %q", overseas.Amount.String(), "-3198")
+	}
+	if overseas.ForeignSummary() != "USD 21.15 @ 151.20" {
+		t.Errorf("foreignSummary() = %q, want %q", overseas.ForeignSummary(), "USD 21.15 @ 151.20")
+	}
+	if result.ValidRecords[1].HasForeignAmount() {
+		t.Errorf("domestic record has foreign amount %+v", result.ValidRecords[1].ForeignAmount)
+	}
+}
@@ -1,7 +1,9 @@
-package main
+package parsers
 
 import (
 	"testing"
+
+	"cppcho.com/ynab_import/encoding"
 )
 
 func TestSmbc_Name(t *testing.T) {
@@ -12,13 +14,13 @@ func TestSmbc_Name(t *testing.T) {
 }
 
 func TestSmbc_Parse_ValidCSV(t *testing.T) {
-	records, err := readCsvToRawRecords("testdata/parsers/smbc_valid.csv")
+	records, err := encoding.ReadCSVFile("testdata/smbc_valid.csv")
 	if err != nil {
-		t.Fatalf("readCsvToRawRecords() error = %v", err)
+		t.Fatalf("encoding.ReadCSVFile() error = %v", err)
 	}
 
 	parser := Smbc{}
-	result, err := parser.Parse(records)
+	result, err := Parse(parser, records)
 	if err != nil {
 		t.Fatalf("Parse() unexpected error: %v", err)
 	}
@@ -34,32 +36,32 @@ func TestSmbc_Parse_ValidCSV(t *testing.T) {
 
 	// Verify first record (deposit: お預入れ column has value)
 	if len(result.ValidRecords) > 0 {
-		if result.ValidRecords[0].date != "2025-12-26" {
-			t.Errorf("Record[0].date = %q, want %q", result.ValidRecords[0].date, "2025-12-26")
+		if result.ValidRecords[0].Date != "2025-12-26" {
+			t.Errorf("Record[0].Date = %q, want %q", result.ValidRecords[0].Date, "2025-12-26")
 		}
-		if result.ValidRecords[0].amount != "31113" { // お預入れ value, not flipped
-			t.Errorf("Record[0].amount = %q, want %q", result.ValidRecords[0].amount, "31113")
+		if result.ValidRecords[0].Amount.String() != "31113" { // お預入れ value, not flipped
+			t.Errorf("Record[0].Amount = %q, want %q", result.ValidRecords[0].Amount.String(), "31113")
 		}
 	}
 
 	// Verify second record (withdrawal: お引出し column has value, should be flipped)
 	if len(result.ValidRecords) > 1 {
 		// Original value is 23000 in お引出し, should be flipped to -23000
-		if result.ValidRecords[1].amount != "-23000" {
-			t.Errorf("Record[1].amount = %q, want %q (flipSign applied)", result.ValidRecords[1].amount, "-23000")
+		if result.ValidRecords[1].Amount.String() != "-23000" {
+			t.Errorf("Record[1].Amount = %q, want %q (sign flipped)", result.ValidRecords[1].Amount.String(), "-23000")
 		}
 	}
 }
 
 func TestSmbc_Parse_WrongHeaders(t *testing.T) {
 	// Use a different parser's CSV
-	records, err := readCsvToRawRecords("testdata/parsers/rakuten_valid.csv")
+	records, err := encoding.ReadCSVFile("testdata/rakuten_valid.csv")
 	if err != nil {
-		t.Fatalf("readCsvToRawRecords() error = %v", err)
+		t.Fatalf("encoding.ReadCSVFile() error = %v", err)
 	}
 
 	parser := Smbc{}
-	result, err := parser.Parse(records)
+	result, err := Parse(parser, records)
 	if err != nil {
 		t.Errorf("Parse() unexpected error: %v", err)
 	}
@@ -71,7 +73,7 @@ func TestSmbc_Parse_WrongHeaders(t *testing.T) {
 
 func TestSmbc_Parse_EmptyRecords(t *testing.T) {
 	parser := Smbc{}
-	result, err := parser.Parse([][]string{})
+	result, err := Parse(parser, [][]string{})
 
 	if err != nil {
 		t.Errorf("Parse() unexpected error for empty input: %v", err)
@@ -91,7 +93,7 @@ func TestSmbc_Parse_InvalidDate(t *testing.T) {
 		{"2025/1/6", "", "3000", "Valid", "15000", "", ""},
 	}
 
-	result, err := parser.Parse(mockRecords)
+	result, err := Parse(parser, mockRecords)
 	if err != nil {
 		t.Fatalf("Parse() unexpected error: %v", err)
 	}
@@ -123,7 +125,7 @@ func TestSmbc_Parse_DateConversion(t *testing.T) {
 		{"2025/1/5", "", "1000", "Test", "10000", "", ""},
 	}
 
-	result, err := parser.Parse(mockRecords)
+	result, err := Parse(parser, mockRecords)
 	if err != nil {
 		t.Fatalf("Parse() unexpected error: %v", err)
 	}
@@ -132,8 +134,8 @@ func TestSmbc_Parse_DateConversion(t *testing.T) {
 	}
 
 	if len(result.ValidRecords) > 0 {
-		if result.ValidRecords[0].date != "2025-01-05" {
-			t.Errorf("Date conversion failed: got %q, want %q", result.ValidRecords[0].date, "2025-01-05")
+		if result.ValidRecords[0].Date != "2025-01-05" {
+			t.Errorf("Date conversion failed: got %q, want %q", result.ValidRecords[0].Date, "2025-01-05")
 		}
 	}
 }
@@ -159,7 +161,7 @@ func TestSmbc_Parse_AmountHandling(t *testing.T) {
 				{"2025/1/1", tt.withdrawal, tt.deposit, "Test", "10000", "", ""},
 			}
 
-			result, err := parser.Parse(mockRecords)
+			result, err := Parse(parser, mockRecords)
 			if err != nil {
 				t.Fatalf("Parse() unexpected error: %v", err)
 			}
@@ -167,9 +169,39 @@ func TestSmbc_Parse_AmountHandling(t *testing.T) {
 				t.Fatal("Parse() returned nil or empty")
 			}
 
-			if result.ValidRecords[0].amount != tt.expectedAmount {
-				t.Errorf("Amount = %q, want %q", result.ValidRecords[0].amount, tt.expectedAmount)
+			if result.ValidRecords[0].Amount.String() != tt.expectedAmount {
+				t.Errorf("Amount = %q, want %q", result.ValidRecords[0].Amount.String(), tt.expectedAmount)
 			}
 		})
 	}
 }
+
+func TestSmbc_Parse_InvalidAmount(t *testing.T) {
+	parser := Smbc{}
+
+	mockRecords := [][]string{
+		{"年月日", "お引出し", "お預入れ", "お取り扱い内容", "残高", "メモ", "ラベル"},
+		{"2025/1/5", "", "12.50", "Decimal", "10000", "", ""},
+		{"2025/1/6", "abc", "", "Garbage", "10000", "", ""}, // Should skip, not become 0
+		{"2025/1/7", "", "", "Empty", "10000", "", ""},      // Should skip, not become 0
+	}
+
+	result, err := Parse(parser, mockRecords)
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+
+	if len(result.ValidRecords) != 1 {
+		t.Fatalf("Parse() returned %d valid records, want 1", len(result.ValidRecords))
+	}
+	if result.ValidRecords[0].Amount.String() != "12.5" {
+		t.Errorf("Amount = %q, want %q (decimals kept)", result.ValidRecords[0].Amount.String(), "12.5")
+	}
+
+	if len(result.SkippedRows) != 2 {
+		t.Fatalf("Parse() returned %d skipped rows, want 2", len(result.SkippedRows))
+	}
+	if result.SkippedRows[0].RowNumber != 3 || result.SkippedRows[1].RowNumber != 4 {
+		t.Errorf("SkippedRows = %+v, want rows 3 and 4", result.SkippedRows)
+	}
+}
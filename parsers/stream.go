@@ -0,0 +1,126 @@
+package parsers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DetectRows is the number of rows at the start of a file that parsers
+// detect their format from
+const DetectRows = 20
+
+// RowReader returns the rows of a CSV file one at a time and io.EOF after
+// the last one, like *csv.Reader
+type RowReader interface {
+	Read() ([]string, error)
+}
+
+type sliceRows struct {
+	records [][]string
+}
+
+// SliceRows reads rows that are already in memory
+func SliceRows(records [][]string) RowReader {
+	return &sliceRows{records: records}
+}
+
+func (s *sliceRows) Read() ([]string, error) {
+	if len(s.records) == 0 {
+		return nil, io.EOF
+	}
+	row := s.records[0]
+	s.records = s.records[1:]
+	return row, nil
+}
+
+type peekedRows struct {
+	head [][]string
+	rows RowReader
+}
+
+func (p *peekedRows) Read() ([]string, error) {
+	if len(p.head) > 0 {
+		row := p.head[0]
+		p.head = p.head[1:]
+		return row, nil
+	}
+	return p.rows.Read()
+}
+
+// Peek reads up to n rows for detection. The returned reader starts again
+// from the first of them.
+func Peek(rows RowReader, n int) ([][]string, RowReader, error) {
+	var head [][]string
+	for len(head) < n {
+		row, err := rows.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read row %d: %w", len(head)+1, err)
+		}
+		head = append(head, row)
+	}
+	return head, &peekedRows{head: head, rows: rows}, nil
+}
+
+// ParseRows parses rows with parser, which must have recognized them, and
+// assigns import IDs. Only the transactions and the skipped rows are kept,
+// not the file.
+func ParseRows(parser Parser, rows RowReader) (*ParseResult, error) {
+	parsed := &ParseResult{}
+	skipped, err := StreamRows(parser, rows, func(record Transaction) error {
+		parsed.ValidRecords = append(parsed.ValidRecords, record)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	parsed.SkippedRows = skipped
+	return parsed, nil
+}
+
+// StreamRows is ParseRows for files too large to hold: every transaction is
+// passed to emit, with its import ID, as soon as it is parsed. Only the
+// skipped rows are kept. An error from emit stops parsing.
+func StreamRows(parser Parser, rows RowReader, emit func(Transaction) error) (skipped []SkippedRow, err error) {
+	// A bug in one parser must not take down a whole directory run
+	defer func() {
+		if r := recover(); r != nil {
+			skipped, err = nil, fmt.Errorf("parser %s panicked: %v", parser.Name(), r)
+		}
+	}()
+
+	ids := newImportIDs(parser.Name())
+	headerRows := parser.HeaderRows()
+	for rowNumber := 1; ; rowNumber++ { // 1-based row number in the file
+		row, err := rows.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read row %d: %w", rowNumber, err)
+		}
+		if rowNumber <= headerRows {
+			continue
+		}
+
+		record, err := parser.ParseRow(row)
+		if err != nil {
+			skipped = append(skipped, SkippedRow{
+				RowNumber: rowNumber,
+				RawData:   row,
+				Reason:    err.Error(),
+			})
+			continue
+		}
+		if record != nil {
+			ids.assign(record)
+			if err := emit(*record); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return skipped, nil
+}
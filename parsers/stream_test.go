@@ -0,0 +1,130 @@
+package parsers
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// smbcRows generates an SMBC export of n transactions, with a bad row every
+// 1000 rows, without holding it in memory
+type smbcRows struct {
+	n, read int
+}
+
+func (s *smbcRows) Read() ([]string, error) {
+	defer func() { s.read++ }()
+	switch {
+	case s.read == 0:
+		return []string{"年月日", "お引出し", "お預入れ", "お取り扱い内容", "残高", "メモ", "ラベル"}, nil
+	case s.read > s.n:
+		return nil, io.EOF
+	case s.read%1000 == 0:
+		return []string{"bad date", "100", "", "テスト", "", "", ""}, nil
+	}
+	return []string{"2025/12/1", "100", "", fmt.Sprintf("テスト%d", s.read), "", "", ""}, nil
+}
+
+func TestPeek(t *testing.T) {
+	records := [][]string{{"a"}, {"b"}, {"c"}}
+
+	head, rows, err := Peek(SliceRows(records), 2)
+	if err != nil {
+		t.Fatalf("Peek() error = %v", err)
+	}
+	if !reflect.DeepEqual(head, records[:2]) {
+		t.Errorf("Peek() head = %q, want %q", head, records[:2])
+	}
+
+	var replayed [][]string
+	for {
+		row, err := rows.Read()
+		if err == io.EOF {
+			break
+		}
+		replayed = append(replayed, row)
+	}
+	if !reflect.DeepEqual(replayed, records) {
+		t.Errorf("rows after Peek() = %q, want %q", replayed, records)
+	}
+}
+
+func TestMatchRows_Streams(t *testing.T) {
+	rows := &smbcRows{n: 20000}
+	parser, result, err := MatchRows(Builtin(), rows)
+	if err != nil {
+		t.Fatalf("MatchRows() error = %v", err)
+	}
+	if parser == nil || parser.Name() != "smbc" {
+		t.Fatalf("MatchRows() parser = %v, want smbc", parser)
+	}
+
+	if len(result.SkippedRows) != 20 || len(result.ValidRecords) != 19980 {
+		t.Fatalf("MatchRows() got %d valid and %d skipped, want 19980 and 20", len(result.ValidRecords), len(result.SkippedRows))
+	}
+	// Row numbers count the header, as in the file
+	if got := result.SkippedRows[0].RowNumber; got != 1001 {
+		t.Errorf("first skipped row = %d, want 1001", got)
+	}
+	if got := result.ValidRecords[0]; got.Payee != "テスト1" || got.ImportID == "" {
+		t.Errorf("first record = %+v, want テスト1 with an import ID", got)
+	}
+}
+
+func TestParse_NotItsFormat(t *testing.T) {
+	rows := [][]string{{"not", "a", "header"}}
+	for _, parser := range Builtin() {
+		if result, err := Parse(parser, rows); err != nil || result != nil {
+			t.Errorf("Parse(%s) = %v, %v, want nil, nil", parser.Name(), result, err)
+		}
+	}
+}
+
+func TestStreamRows(t *testing.T) {
+	rows := &smbcRows{n: 3000}
+	var emitted []Transaction
+	readAtFirst := 0
+	skipped, err := StreamRows(Smbc{}, rows, func(record Transaction) error {
+		if len(emitted) == 0 {
+			readAtFirst = rows.read
+		}
+		emitted = append(emitted, record)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamRows() error = %v", err)
+	}
+
+	// Each record is emitted as soon as its row is parsed
+	if readAtFirst != 2 {
+		t.Errorf("first record emitted after reading %d rows, want 2", readAtFirst)
+	}
+	if len(emitted) != 2997 || len(skipped) != 3 {
+		t.Fatalf("StreamRows() emitted %d and skipped %d, want 2997 and 3", len(emitted), len(skipped))
+	}
+	// Same IDs as parsing the whole file
+	parsed, err := ParseRows(Smbc{}, &smbcRows{n: 3000})
+	if err != nil {
+		t.Fatalf("ParseRows() error = %v", err)
+	}
+	if !reflect.DeepEqual(emitted, parsed.ValidRecords) || !reflect.DeepEqual(skipped, parsed.SkippedRows) {
+		t.Error("StreamRows() differs from ParseRows()")
+	}
+	if emitted[1].ImportID != "smbc:-100000:2025-12-01:2" {
+		t.Errorf("second import ID = %q", emitted[1].ImportID)
+	}
+}
+
+func TestStreamRows_EmitError(t *testing.T) {
+	rows := &smbcRows{n: 3000}
+	_, err := StreamRows(Smbc{}, rows, func(record Transaction) error {
+		return fmt.Errorf("disk full")
+	})
+	if err == nil || err.Error() != "disk full" {
+		t.Errorf("StreamRows() error = %v, want disk full", err)
+	}
+	if rows.read != 2 {
+		t.Errorf("read %d rows, want parsing to stop at the first record", rows.read)
+	}
+}
@@ -0,0 +1,341 @@
+package parsers
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Default payees of Suica charges, set ChargePayee to e.g. "Transfer : View Card"
+// to have YNAB record auto-charges as transfers from the card
+const (
+	DefaultSuicaChargePayee     = "チャージ"
+	DefaultSuicaCashChargePayee = "現金チャージ"
+)
+
+// Suica reads Mobile Suica history PDFs
+type Suica struct {
+	Now             func() time.Time // Defaults to time.Now, bounds histories without an issue date
+	ChargePayee     string           // Payee of auto-charges and card charges
+	CashChargePayee string           // Payee of cash charges at a ticket machine
+}
+
+func (p Suica) Name() string {
+	return "suica"
+}
+
+func (p Suica) Detect(text string) Detection {
+	// The title is the only fixed text, columns move between versions
+	if !strings.Contains(text, "Ｓｕｉｃａ") {
+		return rejected("no %q title", "Ｓｕｉｃａ")
+	}
+	if !strings.Contains(text, "残高ご利用明細") {
+		return rejected("no %q heading", "残高ご利用明細")
+	}
+	return matched(ScoreHeader)
+}
+
+// Parse dates the rows in the current year, ParseFile reads the year from
+// the file name
+func (p Suica) Parse(text string) (*ParseResult, error) {
+	return p.ParseFile("", text)
+}
+
+func (p Suica) ParseFile(fileName, text string) (*ParseResult, error) {
+	if p.Detect(text).Score == ScoreNone {
+		return nil, nil // Not my format
+	}
+
+	// The history only prints month and day. Which years they fall in
+	// comes from the period the history covers, printed or in the file name.
+	period := suicaPeriodFromFileName(fileName)
+	if printed, ok := suicaPeriodFromText(text); ok {
+		period = printed
+	}
+	if period.end.IsZero() {
+		now := time.Now
+		if p.Now != nil {
+			now = p.Now
+		}
+		period.end = now()
+	}
+
+	// Parse transactions from text
+	return p.parseTransactions(text, period)
+}
+
+// suicaPeriod is the span a history covers, start is zero when unknown
+type suicaPeriod struct {
+	start, end time.Time
+}
+
+var suicaFileNamePattern = regexp.MustCompile(`_(\d{8})_(\d{14})?`)
+
+// suicaPeriodFromFileName reads JE000000000000000_20251028_20260101110125.pdf,
+// the start of the history followed by the time the PDF was issued
+func suicaPeriodFromFileName(fileName string) suicaPeriod {
+	var period suicaPeriod
+	matches := suicaFileNamePattern.FindStringSubmatch(fileName)
+	if matches == nil {
+		return period
+	}
+	if start, err := time.Parse("20060102", matches[1]); err == nil {
+		period.start = start
+	}
+	if issued, err := time.Parse("20060102150405", matches[2]); err == nil {
+		period.end = issued
+	}
+	return period
+}
+
+var suicaPrintedPeriodPattern = regexp.MustCompile(
+	`(\d{4})[年/.]\s*(\d{1,2})[月/.]\s*(\d{1,2})日?\s*[～〜~－-]\s*(\d{4})[年/.]\s*(\d{1,2})[月/.]\s*(\d{1,2})日?`)
+
+// suicaPeriodFromText finds a printed period like 2025年10月28日～2026年01月01日
+func suicaPeriodFromText(text string) (suicaPeriod, bool) {
+	matches := suicaPrintedPeriodPattern.FindStringSubmatch(text)
+	if matches == nil {
+		return suicaPeriod{}, false
+	}
+	start, ok := validDate(matches[1], matches[2], matches[3])
+	if !ok {
+		return suicaPeriod{}, false
+	}
+	end, ok := validDate(matches[4], matches[5], matches[6])
+	if !ok || end.Before(start) {
+		return suicaPeriod{}, false
+	}
+	return suicaPeriod{start: start, end: end}, true
+}
+
+func validDate(year, month, day string) (time.Time, bool) {
+	y, _ := strconv.Atoi(year)
+	m, _ := strconv.Atoi(month)
+	d, _ := strconv.Atoi(day)
+	date := time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC)
+	return date, date.Month() == time.Month(m) && date.Day() == d
+}
+
+func (p Suica) chargePayee() string {
+	if p.ChargePayee != "" {
+		return p.ChargePayee
+	}
+	return DefaultSuicaChargePayee
+}
+
+func (p Suica) cashChargePayee() string {
+	if p.CashChargePayee != "" {
+		return p.CashChargePayee
+	}
+	return DefaultSuicaCashChargePayee
+}
+
+// suicaRideMemo turns [from stations..., 出, to stations...] into "from -> to"
+func suicaRideMemo(place []string) string {
+	for i, field := range place {
+		if field != "出" {
+			continue
+		}
+		// Station names may have been split by the extractor
+		from, to := strings.Join(place[:i], ""), strings.Join(place[i+1:], "")
+		if from != "" && to != "" {
+			return fmt.Sprintf("%s -> %s", from, to)
+		}
+		break
+	}
+	return ""
+}
+
+// suicaRow is a history line that starts with a month and day
+type suicaRow struct {
+	lineNumber int
+	fields     []string
+	month      int
+	day        int
+}
+
+// suicaYears assigns a year to every row. The history is in date order, so
+// the year changes where the month goes backwards (December -> January).
+// Years count up from the start of the period when it is known, otherwise
+// down from its end.
+func suicaYears(rows []suicaRow, period suicaPeriod) []int {
+	years := make([]int, len(rows))
+	if len(rows) == 0 {
+		return years
+	}
+
+	if !period.start.IsZero() {
+		year := period.start.Year()
+		if compareMonthDay(rows[0], period.start) < 0 {
+			year++
+		}
+		for i := range rows {
+			if i > 0 && rows[i].month < rows[i-1].month {
+				year++
+			}
+			years[i] = year
+		}
+		return years
+	}
+
+	year := period.end.Year()
+	last := len(rows) - 1
+	if compareMonthDay(rows[last], period.end) > 0 {
+		year--
+	}
+	for i := last; i >= 0; i-- {
+		if i < last && rows[i].month > rows[i+1].month {
+			year--
+		}
+		years[i] = year
+	}
+	return years
+}
+
+// compareMonthDay orders the row and date within a year: -1, 0 or +1
+func compareMonthDay(row suicaRow, date time.Time) int {
+	a := row.month*100 + row.day
+	b := int(date.Month())*100 + date.Day()
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func (p Suica) parseTransactions(text string, period suicaPeriod) (*ParseResult, error) {
+	var validRecords []Transaction
+	var skippedRows []SkippedRow
+
+	lines := strings.Split(text, "\n")
+
+	// The extractor gives us one transaction per line:
+	// Format: "月 日 種別 利用駅 種別 利用駅 残高 入金・利用額"
+	// Example: "12      27   入       京王橋本     出      調布           \14,173         -314"
+	// Example: "12      27   物販                                   \14,487         -170"
+
+	var rows []suicaRow
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+
+		// Skip empty lines and headers
+		if line == "" || strings.Contains(line, "モバイル") || strings.Contains(line, "残高履歴") {
+			continue
+		}
+
+		// Split by whitespace
+		fields := strings.Fields(line)
+
+		if len(fields) < 4 {
+			continue
+		}
+
+		// Check if first field is month (1-12)
+		month, err := strconv.Atoi(fields[0])
+		if err != nil || month < 1 || month > 12 {
+			continue
+		}
+
+		// Check if second field is day (1-31)
+		day, err := strconv.Atoi(fields[1])
+		if err != nil || day < 1 || day > 31 {
+			continue
+		}
+
+		rows = append(rows, suicaRow{lineNumber: i + 1, fields: fields, month: month, day: day})
+	}
+
+	// Rows on the last day of the period are still in it
+	lastDay := time.Date(period.end.Year(), period.end.Month(), period.end.Day(), 0, 0, 0, 0, time.UTC)
+
+	years := suicaYears(rows, period)
+	for i, row := range rows {
+		fields := row.fields
+		skip := func(reason string) {
+			skippedRows = append(skippedRows, SkippedRow{
+				RowNumber: row.lineNumber,
+				RawData:   fields,
+				Reason:    reason,
+			})
+		}
+
+		date, ok := validDate(strconv.Itoa(years[i]), strconv.Itoa(row.month), strconv.Itoa(row.day))
+		if !ok {
+			skip(fmt.Sprintf("invalid date %d/%d", row.month, row.day))
+			continue
+		}
+		if date.After(lastDay) {
+			skip(fmt.Sprintf("date %s is after the end of the history on %s", date.Format("2006-01-02"), lastDay.Format("2006-01-02")))
+			continue
+		}
+
+		// Last field should be amount (starts with + or -)
+		amountStr := fields[len(fields)-1]
+		if !strings.HasPrefix(amountStr, "+") && !strings.HasPrefix(amountStr, "-") {
+			skip(fmt.Sprintf("no amount at the end of the row, got %q", amountStr))
+			continue
+		}
+		amount, err := ParseMoney(amountStr, "JPY")
+		if err != nil {
+			skip(err.Error())
+			continue
+		}
+
+		// The balance (starts with \) comes before the amount. The fields
+		// between the type and the balance are stations or the place of use.
+		balanceIndex := len(fields) - 1
+		for j := 3; j < len(fields)-1; j++ {
+			if strings.HasPrefix(fields[j], "\\") {
+				balanceIndex = j
+				break
+			}
+		}
+		place := fields[3:balanceIndex]
+
+		record := Transaction{Date: date.Format("2006-01-02"), Amount: amount}
+		if balanceIndex < len(fields)-1 {
+			record.Balance = parseBalance(fields[balanceIndex], "JPY")
+		}
+
+		// Third field is the transaction type, usually in half-width katakana
+		switch norm.NFKC.String(fields[2]) {
+		case "入":
+			record.Payee = "交通"
+			record.Memo = suicaRideMemo(place)
+		case "バス":
+			record.Payee = "バス"
+			record.Memo = strings.Join(place, "")
+		case "物販":
+			record.Payee = "物販"
+			record.Memo = strings.Join(place, "")
+		case "オート":
+			record.Payee = p.chargePayee()
+			record.Memo = "オートチャージ"
+		case "チャージ", "カード":
+			record.Payee = p.chargePayee()
+			record.Memo = "チャージ"
+		case "現金":
+			record.Payee = p.cashChargePayee()
+			record.Memo = "現金チャージ"
+		case "払戻":
+			record.Payee = "払戻"
+			record.Memo = strings.Join(place, "")
+		default:
+			skip(fmt.Sprintf("unknown transaction type %q", fields[2]))
+			continue
+		}
+
+		validRecords = append(validRecords, record)
+	}
+
+	return &ParseResult{
+		ValidRecords: validRecords,
+		SkippedRows:  skippedRows,
+	}, nil
+}
@@ -0,0 +1,215 @@
+package parsers
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"cppcho.com/ynab_import/pdftext"
+)
+
+func TestSuica_Name(t *testing.T) {
+	parser := Suica{}
+	if parser.Name() != "suica" {
+		t.Errorf("Name() = %q, want %q", parser.Name(), "suica")
+	}
+}
+
+func TestSuica_Parse_NotSuica(t *testing.T) {
+	result, err := Suica{}.Parse("ご利用明細書\n2025/12/01 テスト 1,000\n")
+	if result != nil || err != nil {
+		t.Errorf("Parse() = %v, %v, want nil, nil", result, err)
+	}
+}
+
+// suicaFixture is generated by go test ./pdftext -update
+const suicaFixture = "testdata/JE000000000000000_20251028_20260101110125.pdf"
+
+func TestSuica_ParseFile(t *testing.T) {
+	text, err := pdftext.Default.Extract(suicaFixture)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if detection := (Suica{}).Detect(text); detection.Score != ScoreHeader {
+		t.Fatalf("Detect() = %+v, want score %d", detection, ScoreHeader)
+	}
+
+	result, err := Suica{}.ParseFile(filepath.Base(suicaFixture), text)
+	if err != nil || result == nil {
+		t.Fatalf("ParseFile() = %v, %v", result, err)
+	}
+
+	want := []Transaction{
+		{Date: "2025-12-27", Payee: "交通", Memo: "京王橋本 -> 調布", Amount: Yen(-314), Balance: Yen(14173)},
+		{Date: "2025-12-28", Payee: "物販", Amount: Yen(-500), Balance: Yen(13673)},
+		{Date: "2025-12-29", Payee: DefaultSuicaChargePayee, Memo: "オートチャージ", Amount: Yen(3000), Balance: Yen(16673)},
+	}
+	if !reflect.DeepEqual(result.ValidRecords, want) {
+		t.Errorf("ParseFile() records:\n%+v\nwant:\n%+v", result.ValidRecords, want)
+	}
+
+	// The last row is dated 1/3, after the history ends on 2026-01-01
+	wantSkipped := []SkippedRow{{
+		RowNumber: 6,
+		RawData:   []string{"1", "3", "入", "新宿", "出", "京王橋本", "\\16,239", "-434"},
+		Reason:    "date 2026-01-03 is after the end of the history on 2026-01-01",
+	}}
+	if !reflect.DeepEqual(result.SkippedRows, wantSkipped) {
+		t.Errorf("ParseFile() skipped rows = %+v, want %+v", result.SkippedRows, wantSkipped)
+	}
+
+	// With charges included the printed balances add up
+	if breaks := ReconcileBalances(result.ValidRecords); len(breaks) != 0 {
+		t.Errorf("ReconcileBalances() = %+v, want no breaks", breaks)
+	}
+}
+
+func TestSuica_ParseFile_Types(t *testing.T) {
+	text := "モバイルＳｕｉｃａ 残高ご利用明細\n" +
+		"12 1 ｶｰﾄﾞ ﾓﾊﾞｲﾙ \\3,000 +3,000\n" +
+		"12 2 ﾊﾞｽ 京王バス \\2,780 -220\n" +
+		"12 3 現金 新宿 \\3,780 +1,000\n" +
+		"12 4 ﾁｬｰｼﾞ \\4,780 +1,000\n" +
+		"12 5 払戻 新宿 \\4,000 -780\n" +
+		"12 6 ｸﾞﾘｰﾝ 東京 \\3,000 -1,000\n" +
+		"12 7 物販 \\2,900\n"
+	parser := Suica{ChargePayee: "Transfer : View Card", CashChargePayee: "Transfer : Cash"}
+	result, err := parser.ParseFile("JE000000000000000_20251201_20251210090000.pdf", text)
+	if err != nil || result == nil {
+		t.Fatalf("ParseFile() = %v, %v", result, err)
+	}
+
+	want := []struct {
+		payee  string
+		memo   string
+		amount Money
+	}{
+		{"Transfer : View Card", "チャージ", Yen(3000)},
+		{"バス", "京王バス", Yen(-220)},
+		{"Transfer : Cash", "現金チャージ", Yen(1000)},
+		{"Transfer : View Card", "チャージ", Yen(1000)},
+		{"払戻", "新宿", Yen(-780)},
+	}
+	if len(result.ValidRecords) != len(want) {
+		t.Fatalf("ParseFile() returned %d records, want %d: %+v", len(result.ValidRecords), len(want), result.ValidRecords)
+	}
+	for i, w := range want {
+		if got := result.ValidRecords[i]; got.Payee != w.payee || got.Memo != w.memo || got.Amount != w.amount {
+			t.Errorf("record %d = %+v, want %+v", i, got, w)
+		}
+	}
+
+	// Nothing is dropped silently
+	wantSkipped := []SkippedRow{
+		{RowNumber: 7, RawData: []string{"12", "6", "ｸﾞﾘｰﾝ", "東京", "\\3,000", "-1,000"}, Reason: `unknown transaction type "ｸﾞﾘｰﾝ"`},
+		{RowNumber: 8, RawData: []string{"12", "7", "物販", "\\2,900"}, Reason: `no amount at the end of the row, got "\\2,900"`},
+	}
+	if !reflect.DeepEqual(result.SkippedRows, wantSkipped) {
+		t.Errorf("SkippedRows = %+v, want %+v", result.SkippedRows, wantSkipped)
+	}
+}
+
+func TestSuicaPeriodFromFileName(t *testing.T) {
+	tests := []struct {
+		fileName string
+		start    string
+		end      string
+	}{
+		{"JE000000000000000_20251028_20260101110125.pdf", "2025-10-28", "2026-01-01"},
+		{"test_20230515_something.pdf", "2023-05-15", ""},
+		{"nodate.pdf", "", ""},
+		{"invalid_12345678_test.pdf", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fileName, func(t *testing.T) {
+			period := suicaPeriodFromFileName(tt.fileName)
+			if got := formatOptionalDate(period.start); got != tt.start {
+				t.Errorf("start = %q, want %q", got, tt.start)
+			}
+			if got := formatOptionalDate(period.end); got != tt.end {
+				t.Errorf("end = %q, want %q", got, tt.end)
+			}
+		})
+	}
+}
+
+func formatOptionalDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+	return date.Format("2006-01-02")
+}
+
+func TestSuica_ParseFile_Years(t *testing.T) {
+	history := "モバイルＳｕｉｃａ 残高ご利用明細\n" +
+		"11 30 物販 \\15,000 -100\n" +
+		"12 31 物販 \\14,900 -100\n" +
+		"1 2 物販 \\14,800 -100\n" +
+		"2 1 物販 \\14,700 -100\n"
+	clock := func() time.Time { return time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name     string
+		fileName string
+		text     string
+		dates    []string
+		skipped  []string
+	}{
+		{
+			name:     "counted from the start in the file name",
+			fileName: "JE000000000000000_20251101_20260301110125.pdf",
+			text:     history,
+			dates:    []string{"2025-11-30", "2025-12-31", "2026-01-02", "2026-02-01"},
+		},
+		{
+			name:     "counted back from today without a file name date",
+			fileName: "suica.pdf",
+			text:     history,
+			dates:    []string{"2025-11-30", "2025-12-31", "2026-01-02", "2026-02-01"},
+		},
+		{
+			name:     "printed period wins over the file name",
+			fileName: "JE000000000000000_20231101_20240301110125.pdf",
+			text:     "2024年11月01日～2025年02月28日\n" + history,
+			dates:    []string{"2024-11-30", "2024-12-31", "2025-01-02", "2025-02-01"},
+		},
+		{
+			name:     "rows after the issue date are reported",
+			fileName: "JE000000000000000_20251101_20260115090000.pdf",
+			text:     history,
+			dates:    []string{"2025-11-30", "2025-12-31", "2026-01-02"},
+			skipped:  []string{"date 2026-02-01 is after the end of the history on 2026-01-15"},
+		},
+		{
+			name:     "impossible dates are reported",
+			fileName: "JE000000000000000_20250101_20250401090000.pdf",
+			text:     "モバイルＳｕｉｃａ 残高ご利用明細\n2 29 物販 \\15,000 -100\n",
+			skipped:  []string{"invalid date 2/29"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Suica{Now: clock}.ParseFile(tt.fileName, tt.text)
+			if err != nil || result == nil {
+				t.Fatalf("ParseFile() = %v, %v", result, err)
+			}
+			var dates []string
+			for _, record := range result.ValidRecords {
+				dates = append(dates, record.Date)
+			}
+			if !reflect.DeepEqual(dates, tt.dates) {
+				t.Errorf("dates = %v, want %v", dates, tt.dates)
+			}
+			var reasons []string
+			for _, skipped := range result.SkippedRows {
+				reasons = append(reasons, skipped.Reason)
+			}
+			if !reflect.DeepEqual(reasons, tt.skipped) {
+				t.Errorf("skipped = %v, want %v", reasons, tt.skipped)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package parsers
+
+type View struct{}
+
+func (p View) Name() string {
+	return "view"
+}
+
+func (p View) AccountType() string {
+	return AccountTypeCreditCard
+}
+
+func (p View) Detect(records [][]string) Detection {
+	if len(records) <= 6 {
+		return rejected("too few rows: want more than 6, got %d", len(records))
+	}
+	if cell, _ := cellAt(records, 0, 0); cell != "会員番号" {
+		return rejected("row 1 column 1 is %q, want %q", cell, "会員番号")
+	}
+	if cell, _ := cellAt(records, 4, 0); cell != "ご利用年月日" {
+		return rejected("row 5 column 1 is %q, want %q", cell, "ご利用年月日")
+	}
+	return matched(ScoreLayout)
+}
+
+func (p View) HeaderRows() int {
+	return 6 // Card and statement details above the transactions
+}
+
+func (p View) ParseRow(row []string) (*Transaction, error) {
+	cols := columnsOf(row)
+	dateCell, payee, amountCell := cols.get(0), cols.get(1), cols.get(4)
+	if cols.err != nil {
+		return nil, cols.err
+	}
+	date, err := convertDate("2006/01/02", "2006-01-02", dateCell)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := ParseMoney(amountCell, "JPY")
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{
+		Date:   date,
+		Amount: amount.Neg(),
+		Payee:  payee,
+	}, nil
+}
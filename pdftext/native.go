@@ -0,0 +1,408 @@
+package pdftext
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// Native extracts text in-process. It places every glyph on the page and
+// rebuilds rows from their baselines, so table cells stay on one line in
+// reading order no matter how the PDF generator ordered its drawing.
+type Native struct{}
+
+func (Native) Name() string {
+	return "native"
+}
+
+func (Native) Extract(path string) (text string, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	// The PDF reader panics on malformed files
+	defer func() {
+		if r := recover(); r != nil {
+			text, err = "", fmt.Errorf("failed to extract PDF text: %v", r)
+		}
+	}()
+
+	reader, err := pdf.NewReader(f, info.Size())
+	if err != nil {
+		return "", fmt.Errorf("failed to extract PDF text: %w", err)
+	}
+
+	var b strings.Builder
+	for i := 1; i <= reader.NumPage(); i++ {
+		page := reader.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+		w := &walker{}
+		w.walk(page.V.Key("Contents"), page.Resources(), identity)
+		for _, line := range layoutLines(w.glyphs) {
+			b.WriteString(line)
+			b.WriteByte('\n')
+		}
+		b.WriteByte('\f')
+	}
+	return b.String(), nil
+}
+
+// glyph is one decoded character in page space
+type glyph struct {
+	x, y  float64 // Baseline origin
+	width float64
+	size  float64 // Font size in page space
+	text  string
+}
+
+// matrix is a PDF transformation matrix [a b c d e f]
+type matrix [6]float64
+
+var identity = matrix{1, 0, 0, 1, 0, 0}
+
+// mul returns m followed by n
+func (m matrix) mul(n matrix) matrix {
+	return matrix{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+func translate(tx, ty float64) matrix {
+	return matrix{1, 0, 0, 1, tx, ty}
+}
+
+func matrixOf(args []pdf.Value) matrix {
+	var m matrix
+	for i := range m {
+		m[i] = args[i].Float64()
+	}
+	return m
+}
+
+// textState is the part of the graphics state that positions text
+type textState struct {
+	ctm      matrix
+	tm, tlm  matrix
+	font     *font
+	fontSize float64
+	charSp   float64 // Tc
+	wordSp   float64 // Tw
+	scale    float64 // Tz / 100
+	leading  float64 // TL
+	rise     float64 // Ts
+}
+
+type walker struct {
+	state  textState
+	stack  []textState
+	glyphs []glyph
+}
+
+// walk interprets a content stream, descending into form XObjects
+func (w *walker) walk(content, resources pdf.Value, ctm matrix) {
+	w.state = textState{ctm: ctm, tm: identity, tlm: identity, scale: 1}
+	fonts := map[string]*font{}
+
+	pdf.Interpret(content, func(stk *pdf.Stack, op string) {
+		args := make([]pdf.Value, stk.Len())
+		for i := len(args) - 1; i >= 0; i-- {
+			args[i] = stk.Pop()
+		}
+		s := &w.state
+
+		switch op {
+		case "q":
+			w.stack = append(w.stack, *s)
+		case "Q":
+			if n := len(w.stack); n > 0 {
+				*s = w.stack[n-1]
+				w.stack = w.stack[:n-1]
+			}
+		case "cm":
+			if len(args) == 6 {
+				s.ctm = matrixOf(args).mul(s.ctm)
+			}
+		case "BT":
+			s.tm, s.tlm = identity, identity
+		case "Tf":
+			if len(args) == 2 {
+				name := args[0].Name()
+				if fonts[name] == nil {
+					fonts[name] = newFont(resources.Key("Font").Key(name))
+				}
+				s.font = fonts[name]
+				s.fontSize = args[1].Float64()
+			}
+		case "Tc":
+			if len(args) == 1 {
+				s.charSp = args[0].Float64()
+			}
+		case "Tw":
+			if len(args) == 1 {
+				s.wordSp = args[0].Float64()
+			}
+		case "Tz":
+			if len(args) == 1 {
+				s.scale = args[0].Float64() / 100
+			}
+		case "TL":
+			if len(args) == 1 {
+				s.leading = args[0].Float64()
+			}
+		case "Ts":
+			if len(args) == 1 {
+				s.rise = args[0].Float64()
+			}
+		case "Td", "TD":
+			if len(args) == 2 {
+				if op == "TD" {
+					s.leading = -args[1].Float64()
+				}
+				s.tlm = translate(args[0].Float64(), args[1].Float64()).mul(s.tlm)
+				s.tm = s.tlm
+			}
+		case "Tm":
+			if len(args) == 6 {
+				s.tlm = matrixOf(args)
+				s.tm = s.tlm
+			}
+		case "T*":
+			w.nextLine()
+		case "Tj":
+			if len(args) == 1 {
+				w.show(args[0].RawString())
+			}
+		case "'":
+			if len(args) == 1 {
+				w.nextLine()
+				w.show(args[0].RawString())
+			}
+		case "\"":
+			if len(args) == 3 {
+				s.wordSp, s.charSp = args[0].Float64(), args[1].Float64()
+				w.nextLine()
+				w.show(args[2].RawString())
+			}
+		case "TJ":
+			if len(args) != 1 {
+				return
+			}
+			for i := 0; i < args[0].Len(); i++ {
+				item := args[0].Index(i)
+				if item.Kind() == pdf.String {
+					w.show(item.RawString())
+					continue
+				}
+				// Numbers move the next glyph left, in thousandths of the font size
+				tx := -item.Float64() / 1000 * s.fontSize * s.scale
+				s.tm = translate(tx, 0).mul(s.tm)
+			}
+		case "Do":
+			if len(args) != 1 {
+				return
+			}
+			xobject := resources.Key("XObject").Key(args[0].Name())
+			if xobject.Key("Subtype").Name() != "Form" {
+				return // Images carry no text
+			}
+			formResources := xobject.Key("Resources")
+			if formResources.IsNull() {
+				formResources = resources
+			}
+			formMatrix := identity
+			if m := xobject.Key("Matrix"); m.Len() == 6 {
+				for i := range formMatrix {
+					formMatrix[i] = m.Index(i).Float64()
+				}
+			}
+			saved := *s
+			w.walk(xobject, formResources, formMatrix.mul(s.ctm))
+			w.state = saved
+		}
+	})
+}
+
+func (w *walker) nextLine() {
+	s := &w.state
+	s.tlm = translate(0, -s.leading).mul(s.tlm)
+	s.tm = s.tlm
+}
+
+// show places the glyphs of a string operand
+func (w *walker) show(raw string) {
+	s := &w.state
+	if s.font == nil {
+		return
+	}
+
+	for len(raw) > 0 {
+		n := s.font.codeLength
+		if n > len(raw) {
+			n = len(raw)
+		}
+		code := raw[:n]
+		raw = raw[n:]
+
+		// Text rendering matrix, see PDF 32000-1 9.4.4
+		trm := matrix{s.fontSize * s.scale, 0, 0, s.fontSize, 0, s.rise}.mul(s.tm).mul(s.ctm)
+
+		advance := s.font.width(code)/1000*s.fontSize + s.charSp
+		if code == " " {
+			advance += s.wordSp
+		}
+		s.tm = translate(advance*s.scale, 0).mul(s.tm)
+		end := matrix{s.fontSize * s.scale, 0, 0, s.fontSize, 0, s.rise}.mul(s.tm).mul(s.ctm)
+
+		w.glyphs = append(w.glyphs, glyph{
+			x:     trm[4],
+			y:     trm[5],
+			width: end[4] - trm[4],
+			size:  math.Hypot(trm[2], trm[3]),
+			text:  s.font.decode(code),
+		})
+	}
+}
+
+// font decodes character codes and knows their advance widths
+type font struct {
+	codeLength   int // Bytes per character code
+	encoding     pdf.TextEncoding
+	utf16        bool // Codes are UTF-16 without a ToUnicode map
+	widths       map[int]float64
+	defaultWidth float64
+}
+
+func newFont(v pdf.Value) *font {
+	f := &font{
+		codeLength: 1,
+		encoding:   pdf.Font{V: v}.Encoder(),
+		widths:     map[int]float64{},
+		// Standard 14 fonts come without widths, half an em is close enough
+		// to keep the glyphs of one string together
+		defaultWidth: 500,
+	}
+
+	if v.Key("Subtype").Name() != "Type0" {
+		first := int(v.Key("FirstChar").Int64())
+		widths := v.Key("Widths")
+		for i := 0; i < widths.Len(); i++ {
+			f.widths[first+i] = widths.Index(i).Float64()
+		}
+		return f
+	}
+
+	// Composite fonts (all Japanese statements) use two-byte codes. The
+	// predefined UCS-2 CMaps are plain UTF-16 when there is no ToUnicode map.
+	f.codeLength = 2
+	if v.Key("ToUnicode").Kind() != pdf.Stream {
+		encoding := v.Key("Encoding").Name()
+		f.utf16 = strings.Contains(encoding, "UCS2") || strings.Contains(encoding, "UTF16")
+	}
+
+	descendant := v.Key("DescendantFonts").Index(0)
+	f.defaultWidth = 1000
+	if dw := descendant.Key("DW"); dw.Kind() != pdf.Null {
+		f.defaultWidth = dw.Float64()
+	}
+	// W is a list of "c [w1 w2 ...]" and "cFirst cLast w" entries
+	w := descendant.Key("W")
+	for i := 0; i+1 < w.Len(); {
+		first := int(w.Index(i).Int64())
+		if next := w.Index(i + 1); next.Kind() == pdf.Array {
+			for j := 0; j < next.Len(); j++ {
+				f.widths[first+j] = next.Index(j).Float64()
+			}
+			i += 2
+			continue
+		}
+		if i+2 >= w.Len() {
+			break
+		}
+		last := int(w.Index(i + 1).Int64())
+		for c := first; c <= last; c++ {
+			f.widths[c] = w.Index(i + 2).Float64()
+		}
+		i += 3
+	}
+	return f
+}
+
+func (f *font) width(code string) float64 {
+	c := 0
+	for i := 0; i < len(code); i++ {
+		c = c<<8 | int(code[i])
+	}
+	if width, ok := f.widths[c]; ok {
+		return width
+	}
+	return f.defaultWidth
+}
+
+func (f *font) decode(code string) string {
+	if f.utf16 && len(code) == 2 {
+		return string(utf16.Decode([]uint16{uint16(code[0])<<8 | uint16(code[1])}))
+	}
+	return f.encoding.Decode(code)
+}
+
+// layoutLines groups glyphs into rows by baseline, top to bottom, and joins
+// each row left to right. Gaps wider than a space become spaces, more of
+// them for wider gaps, so columns stay apart like pdftotext -layout output.
+func layoutLines(glyphs []glyph) []string {
+	sort.SliceStable(glyphs, func(i, j int) bool {
+		return glyphs[i].y > glyphs[j].y
+	})
+
+	var rows [][]glyph
+	for i, g := range glyphs {
+		if i > 0 {
+			row := rows[len(rows)-1]
+			if math.Abs(row[0].y-g.y) < row[0].size/2 {
+				rows[len(rows)-1] = append(row, g)
+				continue
+			}
+		}
+		rows = append(rows, []glyph{g})
+	}
+
+	var lines []string
+	for _, row := range rows {
+		sort.SliceStable(row, func(i, j int) bool {
+			return row[i].x < row[j].x
+		})
+
+		var b strings.Builder
+		for i, g := range row {
+			if i > 0 {
+				prev := row[i-1]
+				if gap := g.x - (prev.x + prev.width); gap > g.size/5 {
+					b.WriteString(strings.Repeat(" ", 1+int(gap/g.size)))
+				}
+			}
+			b.WriteString(g.text)
+		}
+		if line := strings.TrimRight(b.String(), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,247 @@
+package pdftext
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+var update = flag.Bool("update", false, "regenerate PDF fixtures")
+
+// suicaFixture is read by the Suica parser tests
+const suicaFixture = "../parsers/testdata/JE000000000000000_20251028_20260101110125.pdf"
+
+// cell is a string drawn at a position on the page
+type cell struct {
+	x, y float64
+	text string
+}
+
+// suicaCells lays out a Mobile Suica history. Cells are drawn column by
+// column, the way table generators often do, so rows only come together
+// through their baselines.
+func suicaCells() []cell {
+	cells := []cell{
+		{40, 800, "モバイルＳｕｉｃａ　残高ご利用明細"},
+		{40, 760, "月"}, {70, 760, "日"}, {100, 760, "種別"}, {140, 760, "利用駅"},
+		{230, 760, "種別"}, {270, 760, "利用駅"}, {380, 760, "残高"}, {460, 760, "入金・利用額"},
+	}
+	rows := [][]string{
+		{"12", "27", "入", "京王橋本", "出", "調布", "\\14,173", "-314"},
+		{"12", "28", "物販", "", "", "", "\\13,673", "-500"},
+		{"12", "29", "ｵｰﾄ", "調布", "", "", "\\16,673", "+3,000"},
+		{"1", "3", "入", "新宿", "出", "京王橋本", "\\16,239", "-434"},
+	}
+	columns := []float64{40, 70, 100, 140, 230, 270, 380, 460}
+	for col, x := range columns {
+		for i, row := range rows {
+			if row[col] != "" {
+				cells = append(cells, cell{x, 740 - float64(i)*20, row[col]})
+			}
+		}
+	}
+	return cells
+}
+
+// buildPDF writes a one page PDF using a composite font with a ToUnicode
+// map, like the fonts of Japanese statements
+func buildPDF(cells []cell, size float64) []byte {
+	cids := map[rune]int{}
+	var runes []rune
+	var content bytes.Buffer
+	for _, c := range cells {
+		var hex strings.Builder
+		for _, r := range c.text {
+			if _, ok := cids[r]; !ok {
+				runes = append(runes, r)
+				cids[r] = len(runes)
+			}
+			fmt.Fprintf(&hex, "%04X", cids[r])
+		}
+		fmt.Fprintf(&content, "BT /F1 %g Tf %g %g Td <%s> Tj ET\n", size, c.x, c.y, hex.String())
+	}
+
+	var widths, toUnicode strings.Builder
+	fmt.Fprintf(&toUnicode, "/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n/CMapName /Test-UCS def\n1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	for start := 0; start < len(runes); start += 100 {
+		end := min(start+100, len(runes))
+		fmt.Fprintf(&toUnicode, "%d beginbfchar\n", end-start)
+		for i := start; i < end; i++ {
+			var unicode strings.Builder
+			for _, u := range utf16.Encode([]rune{runes[i]}) {
+				fmt.Fprintf(&unicode, "%04X", u)
+			}
+			fmt.Fprintf(&toUnicode, "<%04X> <%s>\n", i+1, unicode.String())
+		}
+		fmt.Fprintf(&toUnicode, "endbfchar\n")
+	}
+	fmt.Fprintf(&toUnicode, "endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	for i, r := range runes {
+		// Half-width for ASCII and half-width katakana, full-width otherwise
+		width := 1000
+		if r < 0x80 || (r >= 0xFF61 && r <= 0xFF9F) {
+			width = 500
+		}
+		fmt.Fprintf(&widths, "%d [%d] ", i+1, width)
+	}
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] /Resources << /Font << /F1 5 0 R >> >> /Contents 4 0 R >>",
+		stream(content.String()),
+		"<< /Type /Font /Subtype /Type0 /BaseFont /TestGothic /Encoding /Identity-H /DescendantFonts [6 0 R] /ToUnicode 7 0 R >>",
+		"<< /Type /Font /Subtype /CIDFontType2 /BaseFont /TestGothic /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor 8 0 R /DW 1000 /W [" + widths.String() + "] >>",
+		stream(toUnicode.String()),
+		"<< /Type /FontDescriptor /FontName /TestGothic /Flags 4 /FontBBox [0 -120 1000 880] /ItalicAngle 0 /Ascent 880 /Descent -120 /CapHeight 700 /StemV 80 >>",
+	}
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return out.Bytes()
+}
+
+func stream(data string) string {
+	return fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(data), data)
+}
+
+func writePDF(t *testing.T, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "statement.pdf")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return path
+}
+
+func TestNative_Extract(t *testing.T) {
+	path := writePDF(t, buildPDF(suicaCells(), 10))
+
+	text, err := Native{}.Extract(path)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	var rows [][]string
+	for _, line := range strings.Split(text, "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			rows = append(rows, fields)
+		}
+	}
+	want := [][]string{
+		{"モバイルＳｕｉｃａ", "残高ご利用明細"}, // Fields splits on the ideographic space
+		{"月", "日", "種別", "利用駅", "種別", "利用駅", "残高", "入金・利用額"},
+		{"12", "27", "入", "京王橋本", "出", "調布", "\\14,173", "-314"},
+		{"12", "28", "物販", "\\13,673", "-500"},
+		{"12", "29", "ｵｰﾄ", "調布", "\\16,673", "+3,000"},
+		{"1", "3", "入", "新宿", "出", "京王橋本", "\\16,239", "-434"},
+	}
+	if fmt.Sprint(rows) != fmt.Sprint(want) {
+		t.Errorf("Extract() rows:\n%s\nwant:\n%s", strings.Join(joinRows(rows), "\n"), strings.Join(joinRows(want), "\n"))
+	}
+	if !strings.HasSuffix(text, "\f") {
+		t.Errorf("Extract() should end the page with a form feed: %q", text)
+	}
+}
+
+func joinRows(rows [][]string) []string {
+	var lines []string
+	for _, row := range rows {
+		lines = append(lines, strings.Join(row, " | "))
+	}
+	return lines
+}
+
+func TestNative_Extract_Spacing(t *testing.T) {
+	// Adjacent strings stay together, separate cells get spaces
+	path := writePDF(t, buildPDF([]cell{
+		{100, 700, "京王"}, {120, 700, "橋本"}, // 2 glyphs of 10pt end at 120
+		{200, 700, "-314"},
+	}, 10))
+
+	text, err := Native{}.Extract(path)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	line := strings.TrimSpace(text)
+	if !strings.HasPrefix(line, "京王橋本 ") || !strings.HasSuffix(line, " -314") {
+		t.Errorf("Extract() = %q, want %q then spaces then %q", line, "京王橋本", "-314")
+	}
+}
+
+func TestNative_Extract_Errors(t *testing.T) {
+	if _, err := (Native{}).Extract("testdata/missing.pdf"); err == nil {
+		t.Error("Extract() expected error for a missing file")
+	}
+
+	path := writePDF(t, []byte("年月日,お引出し\n"))
+	if _, err := (Native{}).Extract(path); err == nil {
+		t.Error("Extract() expected error for a non-PDF file")
+	}
+}
+
+// Both backends must yield the same cells. Skipped without poppler.
+func TestNative_MatchesPdftotext(t *testing.T) {
+	if _, err := exec.LookPath("pdftotext"); err != nil {
+		t.Skip("pdftotext not installed")
+	}
+	path := writePDF(t, buildPDF(suicaCells(), 10))
+
+	native, err := Native{}.Extract(path)
+	if err != nil {
+		t.Fatalf("Native.Extract() error = %v", err)
+	}
+	poppler, err := Pdftotext{}.Extract(path)
+	if err != nil {
+		t.Fatalf("Pdftotext.Extract() error = %v", err)
+	}
+	if got, want := strings.Fields(native), strings.Fields(poppler); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("native and pdftotext differ:\n%s\n---\n%s", native, poppler)
+	}
+}
+
+func TestByName(t *testing.T) {
+	for _, name := range []string{"native", "pdftotext"} {
+		extractor, err := ByName(name)
+		if err != nil || extractor.Name() != name {
+			t.Errorf("ByName(%q) = %v, %v", name, extractor, err)
+		}
+	}
+	if _, err := ByName("acrobat"); err == nil {
+		t.Error("ByName() expected error for an unknown extractor")
+	}
+}
+
+// go test ./pdftext -update rewrites the Suica parser fixture
+func TestSuicaFixture(t *testing.T) {
+	data := buildPDF(suicaCells(), 10)
+	if *update {
+		if err := os.WriteFile(suicaFixture, data, 0644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+	got, err := os.ReadFile(suicaFixture)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v (run go test ./pdftext -update)", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("%s is out of date, run go test ./pdftext -update", suicaFixture)
+	}
+}
@@ -0,0 +1,59 @@
+// Package pdftext extracts the text of PDF statements, one line per printed
+// row with the cells of a row separated by spaces
+package pdftext
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Extractor turns a PDF file into plain text. Pages end with a form feed
+// like pdftotext output.
+type Extractor interface {
+	Name() string
+	Extract(path string) (string, error)
+}
+
+// Default is the in-process extractor, it needs no external tools
+var Default Extractor = Native{}
+
+// Extractors lists the available backends by name
+var Extractors = []Extractor{Native{}, Pdftotext{}}
+
+// ByName returns the extractor called name
+func ByName(name string) (Extractor, error) {
+	var names []string
+	for _, extractor := range Extractors {
+		if extractor.Name() == name {
+			return extractor, nil
+		}
+		names = append(names, extractor.Name())
+	}
+	return nil, fmt.Errorf("unknown PDF extractor %q (want %s)", name, strings.Join(names, " or "))
+}
+
+// Pdftotext runs poppler's pdftotext -layout
+type Pdftotext struct{}
+
+func (Pdftotext) Name() string {
+	return "pdftotext"
+}
+
+func (Pdftotext) Extract(path string) (string, error) {
+	cmd := exec.Command("pdftotext", "-layout", path, "-")
+	var out bytes.Buffer
+	var stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return "", fmt.Errorf("pdftotext not found: please install poppler-utils (brew install poppler on macOS, apt-get install poppler-utils on Linux) or use the native extractor")
+		}
+		return "", fmt.Errorf("failed to extract PDF text: %w (stderr: %s)", err, stderr.String())
+	}
+	return out.String(), nil
+}
@@ -1,43 +0,0 @@
-package main
-
-type RakutenCard struct{}
-
-func (p RakutenCard) Name() string {
-	return "rakuten_card"
-}
-
-func (p RakutenCard) Parse(records [][]string) (*ParseResult, error) {
-	// Handle empty records
-	if len(records) == 0 {
-		return nil, nil // Not my format
-	}
-
-	if len(records[0]) != 10 || records[0][9] != "新規サイン" {
-		return nil, nil
-	}
-
-	var validRecords []YnabRecord
-	var skippedRows []SkippedRow
-
-	for i, row := range records[1:] {
-		date, err := convertDate("2006/01/02", "2006-01-02", row[0])
-		if err != nil {
-			skippedRows = append(skippedRows, SkippedRow{
-				RowNumber: i + 2, // +2 for header and 0-index
-				RawData:   row,
-				Reason:    err.Error(),
-			})
-			continue
-		}
-		validRecords = append(validRecords, YnabRecord{
-			date:   date,
-			amount: flipSign(row[6]),
-			payee:  row[1],
-		})
-	}
-
-	return &ParseResult{
-		ValidRecords: validRecords,
-		SkippedRows:  skippedRows,
-	}, nil
-}
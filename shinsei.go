@@ -1,67 +0,0 @@
-package main
-
-import (
-	"reflect"
-)
-
-type Shinsei struct{}
-
-func (p Shinsei) Name() string {
-	return "shinsei"
-}
-
-func (p Shinsei) Parse(records [][]string) (*ParseResult, error) {
-	// Handle empty records
-	if len(records) == 0 {
-		return nil, nil // Not my format
-	}
-
-	// Check for both quoted and unquoted header formats
-	// Also handle BOM on first field
-	validHeaders := [][]string{
-		{"取引日", "摘要", "出金金額", "入金金額", "残高"},
-		{"\"取引日\"", "\"摘要\"", "\"出金金額\"", "\"入金金額\"", "\"残高\""},
-		{"\ufeff\"取引日\"", "摘要", "出金金額", "入金金額", "残高"},
-	}
-
-	matched := false
-	for _, headers := range validHeaders {
-		if reflect.DeepEqual(records[0], headers) {
-			matched = true
-			break
-		}
-	}
-
-	if !matched {
-		return nil, nil
-	}
-
-	var validRecords []YnabRecord
-	var skippedRows []SkippedRow
-
-	for i, row := range records[1:] {
-		amount := row[3]
-		if row[2] != "" {
-			amount = flipSign(row[2])
-		}
-		date, err := convertDate("2006/01/02", "2006-01-02", row[0])
-		if err != nil {
-			skippedRows = append(skippedRows, SkippedRow{
-				RowNumber: i + 2, // +2 for header and 0-index
-				RawData:   row,
-				Reason:    err.Error(),
-			})
-			continue
-		}
-		validRecords = append(validRecords, YnabRecord{
-			date:   date,
-			amount: amount,
-			memo:   row[1],
-		})
-	}
-
-	return &ParseResult{
-		ValidRecords: validRecords,
-		SkippedRows:  skippedRows,
-	}, nil
-}
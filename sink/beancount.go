@@ -0,0 +1,63 @@
+package sink
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// writeBeancount writes a beancount file with one transaction per record
+// and the import ID as metadata. The accounts are left to open in the main
+// ledger, as several files opening the same account fail to load together.
+func writeBeancount(w io.Writer, stmt textStatement) error {
+	for i, record := range stmt.records {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		_, err := fmt.Fprintf(w, "%s * %s %s\n  import_id: %s\n  %s  %s %s\n  %s\n",
+			record.Date, beancountString(record.Payee), beancountString(record.FullMemo()),
+			beancountString(record.ImportID),
+			stmt.account, record.Amount, record.Amount.Currency,
+			counterAccount(record.Amount))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeBeancountWithOpens starts with open directives for every account
+// used, for a file loaded on its own
+func writeBeancountWithOpens(w io.Writer, stmt textStatement) error {
+	if len(stmt.records) == 0 {
+		return nil
+	}
+
+	// Beancount rejects postings to accounts that were never opened
+	opened := map[string]bool{stmt.account: true}
+	for _, record := range stmt.records {
+		opened[counterAccount(record.Amount)] = true
+	}
+	accounts := make([]string, 0, len(opened))
+	for account := range opened {
+		accounts = append(accounts, account)
+	}
+	sort.Strings(accounts)
+
+	for _, account := range accounts {
+		if _, err := fmt.Fprintf(w, "%s open %s\n", stmt.records[0].Date, account); err != nil {
+			return err
+		}
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return err
+	}
+	return writeBeancount(w, stmt)
+}
+
+func beancountString(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(oneLine(value)) + `"`
+}
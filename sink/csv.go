@@ -0,0 +1,87 @@
+package sink
+
+import (
+	"encoding/csv"
+	"os"
+
+	"cppcho.com/ynab_import/parsers"
+)
+
+// writeRecordsToCsv writes the YNAB CSV format; foreign currency details go into the memo
+func writeRecordsToCsv(records []parsers.Transaction, outputPath string) error {
+	return writeCsv(records, outputPath, false)
+}
+
+// writeRecordsToCsvWithFX adds Foreign Amount, Currency and FX Rate columns
+// instead of putting foreign currency details into the memo
+func writeRecordsToCsvWithFX(records []parsers.Transaction, outputPath string) error {
+	return writeCsv(records, outputPath, true)
+}
+
+func writeCsv(records []parsers.Transaction, outputPath string, fxColumns bool) error {
+	w, err := createCsv(outputPath, fxColumns)
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		if err := w.write(record); err != nil {
+			w.close()
+			return err
+		}
+	}
+	return w.close()
+}
+
+// csvWriter writes the YNAB CSV format one record at a time
+type csvWriter struct {
+	f         *os.File
+	w         *csv.Writer
+	fxColumns bool
+}
+
+// createCsv creates outputPath and writes the header
+func createCsv(outputPath string, fxColumns bool) (*csvWriter, error) {
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return nil, err
+	}
+
+	w := csv.NewWriter(f)
+	header := []string{"Date", "Payee", "Memo", "Amount"}
+	if fxColumns {
+		header = append(header, "Foreign Amount", "Currency", "FX Rate")
+	}
+	if err := w.Write(header); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &csvWriter{f: f, w: w, fxColumns: fxColumns}, nil
+}
+
+func (c *csvWriter) write(record parsers.Transaction) error {
+	if record.Date == "" {
+		return nil
+	}
+	var row []string
+	if c.fxColumns {
+		foreignAmount := ""
+		if record.HasForeignAmount() {
+			foreignAmount = record.ForeignAmount.String()
+		}
+		row = []string{record.Date, record.Payee, record.Memo, record.Amount.String(),
+			foreignAmount, record.ForeignAmount.Currency, record.FXRate}
+	} else {
+		row = []string{record.Date, record.Payee, record.FullMemo(), record.Amount.String()}
+	}
+	return c.w.Write(row)
+}
+
+// close flushes the rows and closes the file
+func (c *csvWriter) close() error {
+	c.w.Flush()
+	err := c.w.Error()
+	if closeErr := c.f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,292 @@
+package sink
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cppcho.com/ynab_import/encoding"
+
+	"cppcho.com/ynab_import/parsers"
+)
+
+func TestWriteRecordsToCsv_Basic(t *testing.T) {
+	tempDir := t.TempDir()
+	outputPath := filepath.Join(tempDir, "output.csv")
+
+	records := []parsers.Transaction{
+		{Date: "2024-01-15", Payee: "Store A", Memo: "Purchase", Amount: parsers.Yen(1000)},
+		{Date: "2024-01-16", Payee: "Store B", Memo: "Payment", Amount: parsers.Yen(-500)},
+	}
+
+	err := writeRecordsToCsv(records, outputPath)
+	if err != nil {
+		t.Fatalf("writeRecordsToCsv() error = %v", err)
+	}
+
+	// Verify file exists
+	if _, err := os.Stat(outputPath); os.IsNotExist(err) {
+		t.Errorf("writeRecordsToCsv() did not create file at %s", outputPath)
+	}
+
+	// Read back and verify
+	readRecords, err := encoding.ReadCSVFile(outputPath)
+	if err != nil {
+		t.Fatalf("encoding.ReadCSVFile() error = %v", err)
+	}
+
+	if len(readRecords) != 3 { // header + 2 data rows
+		t.Errorf("got %d rows, want 3", len(readRecords))
+	}
+
+	// Verify header
+	expectedHeader := []string{"Date", "Payee", "Memo", "Amount"}
+	for i, h := range expectedHeader {
+		if readRecords[0][i] != h {
+			t.Errorf("Header[%d] = %q, want %q", i, readRecords[0][i], h)
+		}
+	}
+}
+
+func TestWriteRecordsToCsv_AmountsWrittenAsIs(t *testing.T) {
+	// Amounts are written with their sign unchanged and without losing decimals
+	tempDir := t.TempDir()
+	outputPath := filepath.Join(tempDir, "output.csv")
+
+	records := []parsers.Transaction{
+		{Date: "2024-01-15", Payee: "Test", Memo: "Memo", Amount: parsers.Yen(1000)},
+		{Date: "2024-01-16", Payee: "Test", Memo: "Memo", Amount: parsers.Yen(-500)},
+		{Date: "2024-01-17", Payee: "Test", Memo: "Memo", Amount: parsers.Money{Milliunits: -12500, Currency: "USD"}},
+	}
+
+	err := writeRecordsToCsv(records, outputPath)
+	if err != nil {
+		t.Fatalf("writeRecordsToCsv() error = %v", err)
+	}
+
+	// Read back
+	readRecords, err := encoding.ReadCSVFile(outputPath)
+	if err != nil {
+		t.Fatalf("encoding.ReadCSVFile() error = %v", err)
+	}
+
+	for i, want := range []string{"1000", "-500", "-12.5"} {
+		if readRecords[i+1][3] != want {
+			t.Errorf("Row %d amount = %q, want %q", i+1, readRecords[i+1][3], want)
+		}
+	}
+}
+
+func TestWriteRecordsToCsv_SkipsEmptyDate(t *testing.T) {
+	tempDir := t.TempDir()
+	outputPath := filepath.Join(tempDir, "output.csv")
+
+	records := []parsers.Transaction{
+		{Date: "2024-01-15", Payee: "Valid", Memo: "Valid", Amount: parsers.Yen(1000)},
+		{Date: "", Payee: "No Date", Memo: "Should Skip", Amount: parsers.Yen(500)}, // Empty date - should skip
+		{Date: "2024-01-17", Payee: "Valid", Memo: "Valid", Amount: parsers.Yen(2000)},
+	}
+
+	err := writeRecordsToCsv(records, outputPath)
+	if err != nil {
+		t.Fatalf("writeRecordsToCsv() error = %v", err)
+	}
+
+	// Read back
+	readRecords, err := encoding.ReadCSVFile(outputPath)
+	if err != nil {
+		t.Fatalf("encoding.ReadCSVFile() error = %v", err)
+	}
+
+	// Should have header + 2 valid rows (skipped 1 invalid)
+	if len(readRecords) != 3 {
+		t.Errorf("got %d rows, want 3 (header + 2 valid rows)", len(readRecords))
+	}
+}
+
+func TestWriteRecordsToCsv_EmptyRecordsList(t *testing.T) {
+	tempDir := t.TempDir()
+	outputPath := filepath.Join(tempDir, "output.csv")
+
+	records := []parsers.Transaction{}
+
+	err := writeRecordsToCsv(records, outputPath)
+	if err != nil {
+		t.Fatalf("writeRecordsToCsv() error = %v", err)
+	}
+
+	// Read back
+	readRecords, err := encoding.ReadCSVFile(outputPath)
+	if err != nil {
+		t.Fatalf("encoding.ReadCSVFile() error = %v", err)
+	}
+
+	// Should have only header
+	if len(readRecords) != 1 {
+		t.Errorf("got %d rows, want 1 (header only)", len(readRecords))
+	}
+}
+
+func TestWriteRecordsToCsv_SpecialCharacters(t *testing.T) {
+	tempDir := t.TempDir()
+	outputPath := filepath.Join(tempDir, "output.csv")
+
+	records := []parsers.Transaction{
+		{Date: "2024-01-15", Payee: "Store \"Quotes\"", Memo: "Comma, in memo", Amount: parsers.Yen(1000)},
+		{Date: "2024-01-16", Payee: "New\nLine", Memo: "Tab\there", Amount: parsers.Yen(2000)},
+	}
+
+	err := writeRecordsToCsv(records, outputPath)
+	if err != nil {
+		t.Fatalf("writeRecordsToCsv() error = %v", err)
+	}
+
+	// Read back
+	readRecords, err := encoding.ReadCSVFile(outputPath)
+	if err != nil {
+		t.Fatalf("encoding.ReadCSVFile() error = %v", err)
+	}
+
+	// Verify special characters are preserved
+	if readRecords[1][1] != "Store \"Quotes\"" {
+		t.Errorf("Quotes not preserved: got %q", readRecords[1][1])
+	}
+	if readRecords[1][2] != "Comma, in memo" {
+		t.Errorf("Comma not preserved: got %q", readRecords[1][2])
+	}
+}
+
+func TestWriteRecordsToCsv_InvalidPath(t *testing.T) {
+	// Try to write to a directory that doesn't exist (and we can't create)
+	invalidPath := "/nonexistent/directory/that/should/not/exist/output.csv"
+
+	records := []parsers.Transaction{
+		{Date: "2024-01-15", Payee: "Test", Memo: "Test", Amount: parsers.Yen(1000)},
+	}
+
+	err := writeRecordsToCsv(records, invalidPath)
+	if err == nil {
+		t.Error("writeRecordsToCsv() expected error for invalid path, got nil")
+	}
+}
+
+func TestWriteRecordsToCsv_ForeignCurrency(t *testing.T) {
+	tempDir := t.TempDir()
+
+	records := []parsers.Transaction{
+		{Date: "2024-01-15", Payee: "Overseas", Memo: "US", Amount: parsers.Yen(-1866),
+			ForeignAmount: parsers.Money{Milliunits: -12340, Currency: "USD"}, FXRate: "151.2"},
+		{Date: "2024-01-16", Payee: "Domestic", Amount: parsers.Yen(-500)},
+	}
+
+	// Default: foreign currency details in the memo
+	memoPath := filepath.Join(tempDir, "memo.csv")
+	if err := writeRecordsToCsv(records, memoPath); err != nil {
+		t.Fatalf("writeRecordsToCsv() error = %v", err)
+	}
+	memoRows, err := encoding.ReadCSVFile(memoPath)
+	if err != nil {
+		t.Fatalf("encoding.ReadCSVFile() error = %v", err)
+	}
+	if memoRows[1][2] != "US (USD 12.34 @ 151.2)" {
+		t.Errorf("Memo = %q, want %q", memoRows[1][2], "US (USD 12.34 @ 151.2)")
+	}
+
+	// Rich mode: extra columns, memo untouched
+	richPath := filepath.Join(tempDir, "rich.csv")
+	if err := writeRecordsToCsvWithFX(records, richPath); err != nil {
+		t.Fatalf("writeRecordsToCsvWithFX() error = %v", err)
+	}
+	richRows, err := encoding.ReadCSVFile(richPath)
+	if err != nil {
+		t.Fatalf("encoding.ReadCSVFile() error = %v", err)
+	}
+	expected := [][]string{
+		{"Date", "Payee", "Memo", "Amount", "Foreign Amount", "Currency", "FX Rate"},
+		{"2024-01-15", "Overseas", "US", "-1866", "-12.34", "USD", "151.2"},
+		{"2024-01-16", "Domestic", "", "-500", "", "", ""},
+	}
+	for i, row := range expected {
+		for j, want := range row {
+			if richRows[i][j] != want {
+				t.Errorf("Row %d column %d = %q, want %q", i, j, richRows[i][j], want)
+			}
+		}
+	}
+}
+
+func TestCsvSink_Stream(t *testing.T) {
+	records := []parsers.Transaction{
+		{Date: "2024-01-15", Payee: "ｿｳ ﾀｸﾍｲ", Amount: parsers.Yen(1000)},
+		{Date: "", Payee: "No date", Amount: parsers.Yen(100)},
+		{Date: "2024-01-16", Payee: "Store B", Memo: "Payment", Amount: parsers.Yen(-500)},
+	}
+	written := CsvSink{OutputDir: t.TempDir()}
+	streamed := NormalizeSink{Sink: CsvSink{OutputDir: t.TempDir()}, Default: parsers.NormalizeFull}
+
+	out, ok := Streaming(streamed)
+	if !ok {
+		t.Fatal("Streaming() = false for a normalized CSV sink")
+	}
+	w, err := out.Stream(parsers.Smbc{}, "statement.csv")
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	for _, record := range records {
+		if err := w.WriteRecord(record); err != nil {
+			t.Fatalf("WriteRecord() error = %v", err)
+		}
+	}
+	streamedPath, err := w.Close()
+	if err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// The same file as writing the normalized records at once
+	normalized := append([]parsers.Transaction(nil), records...)
+	parsers.NormalizeRecords(normalized, parsers.NormalizeFull)
+	writtenPath, err := written.Write(parsers.Smbc{}, "statement.csv", &parsers.ParseResult{ValidRecords: normalized})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	got, _ := os.ReadFile(streamedPath)
+	want, _ := os.ReadFile(writtenPath)
+	if string(got) != string(want) {
+		t.Errorf("streamed file:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCsvSink_Stream_Abort(t *testing.T) {
+	outputDir := t.TempDir()
+	w, err := CsvSink{OutputDir: outputDir}.Stream(parsers.Smbc{}, "statement.csv")
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	if err := w.WriteRecord(parsers.Transaction{Date: "2024-01-15", Amount: parsers.Yen(1000)}); err != nil {
+		t.Fatalf("WriteRecord() error = %v", err)
+	}
+	if err := w.Abort(); err != nil {
+		t.Fatalf("Abort() error = %v", err)
+	}
+	if entries, _ := os.ReadDir(outputDir); len(entries) != 0 {
+		t.Errorf("Abort() left %d file(s)", len(entries))
+	}
+}
+
+func TestStreaming(t *testing.T) {
+	tests := []struct {
+		name string
+		out  Sink
+		want bool
+	}{
+		{"csv", CsvSink{}, true},
+		{"normalized csv", NormalizeSink{Sink: CsvSink{}}, true},
+		{"ofx", OfxSink{}, false},
+		{"normalized ledger", NormalizeSink{Sink: LedgerSink{Sink: CsvSink{}}}, false},
+	}
+	for _, tt := range tests {
+		if _, got := Streaming(tt.out); got != tt.want {
+			t.Errorf("Streaming(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package sink
+
+import (
+	"fmt"
+	"io"
+)
+
+// writeHledger writes an hledger journal with one transaction per record.
+// The import ID is kept as an import_id tag so duplicates can be spotted.
+func writeHledger(w io.Writer, stmt textStatement) error {
+	for i, record := range stmt.records {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+
+		// "payee | note" is hledger's syntax for a separate payee
+		description := oneLine(record.Payee)
+		if memo := oneLine(record.FullMemo()); memo != "" {
+			if description == "" {
+				description = memo
+			} else {
+				description += " | " + memo
+			}
+		}
+
+		_, err := fmt.Fprintf(w, "%s %s\n    ; import_id: %s\n    %s  %s %s\n    %s\n",
+			record.Date, description, record.ImportID,
+			stmt.account, record.Amount, record.Amount.Currency,
+			counterAccount(record.Amount))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package sink
+
+import (
+	"fmt"
+	"strings"
+
+	"cppcho.com/ynab_import/parsers"
+)
+
+// Ways InstallmentSink shows payment plans
+const (
+	InstallmentsMemo     = "memo"     // Plan and count in the memo, full amount on the purchase date
+	InstallmentsSchedule = "schedule" // One record per payment, later ones as scheduled transactions
+)
+
+// InstallmentModes are the modes InstallmentSink accepts
+var InstallmentModes = []string{InstallmentsMemo, InstallmentsSchedule}
+
+// InstallmentSink makes 分割, revolving and bonus purchases visible. Both
+// modes note the plan in the memo (e.g. "3 installments from 2026-01");
+// the schedule mode also replaces each installment purchase with its
+// monthly payments.
+type InstallmentSink struct {
+	Sink Sink
+	Mode string
+}
+
+// ParseInstallmentMode validates an -installments value, empty meaning off
+func ParseInstallmentMode(mode string) (string, error) {
+	if mode == "" {
+		return "", nil
+	}
+	for _, known := range InstallmentModes {
+		if mode == known {
+			return mode, nil
+		}
+	}
+	return "", fmt.Errorf("unknown installments mode %q (want one of %s)", mode, strings.Join(InstallmentModes, ", "))
+}
+
+func (s InstallmentSink) Write(parser parsers.Source, fileName string, result *parsers.ParseResult) (string, error) {
+	var records []parsers.Transaction
+	for _, record := range result.ValidRecords {
+		if s.Mode == InstallmentsSchedule && record.Plan.Kind == parsers.PlanInstallments && record.Plan.Count > 1 {
+			records = append(records, record.Installments()...)
+			continue
+		}
+		if summary := record.Plan.Summary(); summary != "" {
+			record.Memo = parsers.AppendMemo(record.Memo, summary)
+		}
+		records = append(records, record)
+	}
+	return s.Sink.Write(parser, fileName, &parsers.ParseResult{ValidRecords: records, SkippedRows: result.SkippedRows})
+}
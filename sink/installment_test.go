@@ -0,0 +1,75 @@
+package sink
+
+import (
+	"testing"
+
+	"cppcho.com/ynab_import/parsers"
+)
+
+func installmentRecords() []parsers.Transaction {
+	return []parsers.Transaction{
+		{Date: "2025-12-20", Payee: "コンビニ", Amount: parsers.Yen(-500), Plan: parsers.PaymentPlan{Kind: parsers.PlanLump, FirstPayment: "2026-01"}},
+		{Date: "2025-12-23", Payee: "家電店", Amount: parsers.Yen(-30000), Plan: parsers.PaymentPlan{Kind: parsers.PlanInstallments, Count: 3, FirstPayment: "2026-01"}},
+		{Date: "2025-12-24", Payee: "書店", Memo: "本", Amount: parsers.Yen(-5000), Plan: parsers.PaymentPlan{Kind: parsers.PlanRevolving}},
+	}
+}
+
+func TestInstallmentSink_Memo(t *testing.T) {
+	out := &recordingSink{}
+	s := InstallmentSink{Sink: out, Mode: InstallmentsMemo}
+
+	if _, err := s.Write(parsers.SmbcCard{}, "statement.csv", &parsers.ParseResult{ValidRecords: installmentRecords()}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if len(out.written) != 3 {
+		t.Fatalf("wrote %d records, want 3", len(out.written))
+	}
+	expected := []string{"", "3 installments from 2026-01", "本, revolving"}
+	for i, memo := range expected {
+		if out.written[i].Memo != memo {
+			t.Errorf("Record[%d].Memo = %q, want %q", i, out.written[i].Memo, memo)
+		}
+	}
+	if out.written[1].Amount != parsers.Yen(-30000) || out.written[1].Date != "2025-12-23" {
+		t.Errorf("Record[1] = %+v, want the full amount on the purchase date", out.written[1])
+	}
+}
+
+func TestInstallmentSink_Schedule(t *testing.T) {
+	out := &recordingSink{}
+	s := InstallmentSink{Sink: out, Mode: InstallmentsSchedule}
+
+	if _, err := s.Write(parsers.SmbcCard{}, "statement.csv", &parsers.ParseResult{ValidRecords: installmentRecords()}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	var got []string
+	for _, record := range out.written {
+		got = append(got, record.Date+" "+record.Amount.String()+" "+record.Memo)
+	}
+	expected := []string{
+		"2025-12-20 -500 ",
+		"2026-01-23 -10000 installment 1/3",
+		"2026-02-23 -10000 installment 2/3",
+		"2026-03-23 -10000 installment 3/3",
+		"2025-12-24 -5000 本, revolving",
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("wrote %q, want %q", got, expected)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("Record[%d] = %q, want %q", i, got[i], expected[i])
+		}
+	}
+}
+
+func TestParseInstallmentMode(t *testing.T) {
+	for _, mode := range []string{"", "memo", "schedule"} {
+		if got, err := ParseInstallmentMode(mode); err != nil || got != mode {
+			t.Errorf("ParseInstallmentMode(%q) = %q, %v", mode, got, err)
+		}
+	}
+	if _, err := ParseInstallmentMode("monthly"); err == nil {
+		t.Error("ParseInstallmentMode() expected error for an unknown mode")
+	}
+}
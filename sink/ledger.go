@@ -0,0 +1,191 @@
+package sink
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"cppcho.com/ynab_import/parsers"
+)
+
+// LedgerFileName is the ledger file in the base output directory
+const LedgerFileName = "ynab_import_ledger.jsonl"
+
+// LedgerEntry is one exported transaction, stored as a JSON line
+type LedgerEntry struct {
+	Account    string `json:"account"`
+	ImportID   string `json:"import_id"`
+	Date       string `json:"date"`
+	Amount     string `json:"amount"`
+	Payee      string `json:"payee,omitempty"`
+	Memo       string `json:"memo,omitempty"`
+	File       string `json:"file"`
+	ExportedAt string `json:"exported_at"`
+}
+
+// Ledger remembers every record that has been written, per account
+// (currently the parser name), keyed by import ID
+type Ledger struct {
+	path    string
+	entries []LedgerEntry
+	seen    map[string]map[string]bool // account -> import ID -> exported
+}
+
+// OpenLedger loads the ledger at path; a missing file is an empty ledger
+func OpenLedger(path string) (*Ledger, error) {
+	l := &Ledger{path: path, seen: map[string]map[string]bool{}}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil // First run
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry LedgerEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("invalid ledger entry at %s:%d: %w", path, lineNumber, err)
+		}
+		l.add(entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *Ledger) add(entry LedgerEntry) {
+	if l.seen[entry.Account] == nil {
+		l.seen[entry.Account] = map[string]bool{}
+	}
+	l.seen[entry.Account][entry.ImportID] = true
+	l.entries = append(l.entries, entry)
+}
+
+// Contains reports whether the import ID was already exported for the account
+func (l *Ledger) Contains(account, importID string) bool {
+	return l.seen[account][importID]
+}
+
+// Record appends records that are not in the ledger yet and saves them
+func (l *Ledger) Record(account, fileName string, records []parsers.Transaction) error {
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	encoder := json.NewEncoder(f)
+	for _, record := range records {
+		if record.ImportID == "" || l.Contains(account, record.ImportID) {
+			continue
+		}
+		entry := LedgerEntry{
+			Account:    account,
+			ImportID:   record.ImportID,
+			Date:       record.Date,
+			Amount:     record.Amount.String(),
+			Payee:      record.Payee,
+			Memo:       record.Memo,
+			File:       fileName,
+			ExportedAt: now,
+		}
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+		l.add(entry)
+	}
+	return nil
+}
+
+// Reset forgets every entry of the account and returns how many were removed
+func (l *Ledger) Reset(account string) (int, error) {
+	var kept []LedgerEntry
+	for _, entry := range l.entries {
+		if entry.Account != account {
+			kept = append(kept, entry)
+		}
+	}
+	removed := len(l.entries) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+
+	// Rewrite through a temp file so a crash never leaves a half-written ledger
+	tmpPath := l.path + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return 0, err
+	}
+	encoder := json.NewEncoder(f)
+	for _, entry := range kept {
+		if err := encoder.Encode(entry); err != nil {
+			f.Close()
+			return 0, err
+		}
+	}
+	if err := f.Close(); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmpPath, l.path); err != nil {
+		return 0, err
+	}
+
+	l.entries = kept
+	delete(l.seen, account)
+	return removed, nil
+}
+
+// LedgerSink writes to Sink only the records that were not exported before
+// and records them in the ledger
+type LedgerSink struct {
+	Sink   Sink
+	Ledger *Ledger
+}
+
+func (s LedgerSink) Write(parser parsers.Source, fileName string, result *parsers.ParseResult) (string, error) {
+	account := parser.Name()
+
+	var fresh []parsers.Transaction
+	alreadyExported := 0
+	for _, record := range result.ValidRecords {
+		if record.ImportID != "" && s.Ledger.Contains(account, record.ImportID) {
+			alreadyExported++
+			continue
+		}
+		fresh = append(fresh, record)
+	}
+	if len(fresh) == 0 {
+		return fmt.Sprintf("nothing (all %d row(s) already exported)", alreadyExported), nil
+	}
+	toWrite := &parsers.ParseResult{ValidRecords: fresh, SkippedRows: result.SkippedRows}
+
+	dest, err := s.Sink.Write(parser, fileName, toWrite)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.Ledger.Record(account, fileName, toWrite.ValidRecords); err != nil {
+		return "", fmt.Errorf("failed to update ledger: %w", err)
+	}
+
+	if alreadyExported > 0 {
+		dest = fmt.Sprintf("%s (%d row(s) already exported)", dest, alreadyExported)
+	}
+	return dest, nil
+}
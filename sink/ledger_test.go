@@ -0,0 +1,131 @@
+package sink
+
+import (
+	"path/filepath"
+	"testing"
+
+	"cppcho.com/ynab_import/parsers"
+)
+
+// recordingSink captures what it was asked to write
+type recordingSink struct {
+	written []parsers.Transaction
+}
+
+func (s *recordingSink) Write(parser parsers.Source, fileName string, result *parsers.ParseResult) (string, error) {
+	s.written = append(s.written, result.ValidRecords...)
+	return "memory", nil
+}
+
+func TestLedger_RecordAndReload(t *testing.T) {
+	ledgerPath := filepath.Join(t.TempDir(), LedgerFileName)
+
+	ledger, err := OpenLedger(ledgerPath)
+	if err != nil {
+		t.Fatalf("OpenLedger() unexpected error: %v", err)
+	}
+
+	records := []parsers.Transaction{
+		{Date: "2025-12-26", Amount: parsers.Yen(-23000), ImportID: "smbc:-23000000:2025-12-26:1"},
+		{Date: "2025-12-26", Amount: parsers.Yen(31113), ImportID: "smbc:31113000:2025-12-26:1"},
+		{Date: "2025-12-27", Amount: parsers.Yen(1)}, // No import ID, not recorded
+	}
+	if err := ledger.Record("smbc", "statement.csv", records); err != nil {
+		t.Fatalf("Record() unexpected error: %v", err)
+	}
+	// Recording the same records again must not duplicate entries
+	if err := ledger.Record("smbc", "statement.csv", records); err != nil {
+		t.Fatalf("Record() unexpected error: %v", err)
+	}
+
+	reloaded, err := OpenLedger(ledgerPath)
+	if err != nil {
+		t.Fatalf("OpenLedger() unexpected error: %v", err)
+	}
+	if len(reloaded.entries) != 2 {
+		t.Errorf("reloaded ledger has %d entries, want 2", len(reloaded.entries))
+	}
+	if !reloaded.Contains("smbc", "smbc:-23000000:2025-12-26:1") {
+		t.Error("Contains() = false for recorded import ID")
+	}
+	if reloaded.Contains("rakuten", "smbc:-23000000:2025-12-26:1") {
+		t.Error("Contains() = true for a different account")
+	}
+}
+
+func TestLedger_Reset(t *testing.T) {
+	ledgerPath := filepath.Join(t.TempDir(), LedgerFileName)
+
+	ledger, err := OpenLedger(ledgerPath)
+	if err != nil {
+		t.Fatalf("OpenLedger() unexpected error: %v", err)
+	}
+	ledger.Record("smbc", "a.csv", []parsers.Transaction{{Date: "2025-12-26", Amount: parsers.Yen(1), ImportID: "smbc:1000:2025-12-26:1"}})
+	ledger.Record("rakuten", "b.csv", []parsers.Transaction{{Date: "2025-12-26", Amount: parsers.Yen(1), ImportID: "rakuten:1000:2025-12-26:1"}})
+
+	removed, err := ledger.Reset("smbc")
+	if err != nil {
+		t.Fatalf("Reset() unexpected error: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("Reset() removed %d entries, want 1", removed)
+	}
+
+	reloaded, err := OpenLedger(ledgerPath)
+	if err != nil {
+		t.Fatalf("OpenLedger() unexpected error: %v", err)
+	}
+	if reloaded.Contains("smbc", "smbc:1000:2025-12-26:1") {
+		t.Error("reset account still present after reload")
+	}
+	if !reloaded.Contains("rakuten", "rakuten:1000:2025-12-26:1") {
+		t.Error("other account was removed by Reset()")
+	}
+}
+
+func TestLedgerSink_SinceLast(t *testing.T) {
+	ledger, err := OpenLedger(filepath.Join(t.TempDir(), LedgerFileName))
+	if err != nil {
+		t.Fatalf("OpenLedger() unexpected error: %v", err)
+	}
+
+	inner := &recordingSink{}
+	sink := LedgerSink{Sink: inner, Ledger: ledger}
+
+	lastWeek := []parsers.Transaction{
+		{Date: "2025-12-25", Amount: parsers.Yen(-5000)},
+		{Date: "2025-12-26", Amount: parsers.Yen(-23000)},
+	}
+	parsers.AssignImportIDs("smbc", lastWeek)
+	if _, err := sink.Write(parsers.Smbc{}, "last_week.csv", &parsers.ParseResult{ValidRecords: lastWeek}); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+
+	// Overlapping export: one old record, one new
+	thisWeek := []parsers.Transaction{
+		{Date: "2025-12-26", Amount: parsers.Yen(-23000)},
+		{Date: "2025-12-27", Amount: parsers.Yen(-1000)},
+	}
+	parsers.AssignImportIDs("smbc", thisWeek)
+	inner.written = nil
+	dest, err := sink.Write(parsers.Smbc{}, "this_week.csv", &parsers.ParseResult{ValidRecords: thisWeek})
+	if err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+
+	if len(inner.written) != 1 || inner.written[0].Date != "2025-12-27" {
+		t.Errorf("Write() passed %v to the sink, want only the 2025-12-27 record", inner.written)
+	}
+	if dest != "memory (1 row(s) already exported)" {
+		t.Errorf("Write() = %q", dest)
+	}
+
+	// Nothing new at all: the inner sink is not called
+	inner.written = nil
+	if _, err := sink.Write(parsers.Smbc{}, "this_week.csv", &parsers.ParseResult{ValidRecords: thisWeek}); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+	if len(inner.written) != 0 {
+		t.Errorf("Write() passed %d records to the sink, want 0", len(inner.written))
+	}
+}
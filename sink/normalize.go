@@ -0,0 +1,87 @@
+package sink
+
+import (
+	"fmt"
+	"strings"
+
+	"cppcho.com/ynab_import/parsers"
+)
+
+// NormalizeSink normalizes payees and memos (see parsers.Normalize) before
+// rules and outputs see them
+type NormalizeSink struct {
+	Sink     Sink
+	Default  string            // Policy of parsers not in Policies, parsers.NormalizeOff when empty
+	Policies map[string]string // Parser name -> policy
+}
+
+func (s NormalizeSink) Write(parser parsers.Source, fileName string, result *parsers.ParseResult) (string, error) {
+	records := append([]parsers.Transaction(nil), result.ValidRecords...)
+	parsers.NormalizeRecords(records, s.policy(parser))
+	return s.Sink.Write(parser, fileName, &parsers.ParseResult{ValidRecords: records, SkippedRows: result.SkippedRows})
+}
+
+// Stream normalizes each record on its way to Sink, which must stream too
+// (see Streaming)
+func (s NormalizeSink) Stream(parser parsers.Source, fileName string) (RecordWriter, error) {
+	inner, ok := s.Sink.(StreamSink)
+	if !ok {
+		return nil, fmt.Errorf("%T cannot write records one at a time", s.Sink)
+	}
+	w, err := inner.Stream(parser, fileName)
+	if err != nil {
+		return nil, err
+	}
+	return normalizeWriter{RecordWriter: w, policy: s.policy(parser)}, nil
+}
+
+func (s NormalizeSink) policy(parser parsers.Source) string {
+	policy := s.Policies[parser.Name()]
+	if policy == "" {
+		policy = s.Default
+	}
+	if policy == "" {
+		policy = parsers.NormalizeOff
+	}
+	return policy
+}
+
+type normalizeWriter struct {
+	RecordWriter
+	policy string
+}
+
+func (w normalizeWriter) WriteRecord(record parsers.Transaction) error {
+	records := []parsers.Transaction{record}
+	parsers.NormalizeRecords(records, w.policy)
+	return w.RecordWriter.WriteRecord(records[0])
+}
+
+// ParseNormalizePolicies parses "half,shinsei=full,suica=off": entries
+// with a parser name set its policy, a bare policy sets the default
+func ParseNormalizePolicies(value string) (string, map[string]string, error) {
+	defaultPolicy := ""
+	policies := map[string]string{}
+	if strings.TrimSpace(value) == "" {
+		return defaultPolicy, policies, nil
+	}
+
+	for _, entry := range strings.Split(value, ",") {
+		name, policy, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			name, policy = "", name
+		}
+		if err := parsers.CheckNormalizePolicy(policy); err != nil {
+			return "", nil, err
+		}
+		if ok && name == "" {
+			return "", nil, fmt.Errorf("invalid normalization %q (want policy or parser=policy)", entry)
+		}
+		if name == "" {
+			defaultPolicy = policy
+		} else {
+			policies[name] = policy
+		}
+	}
+	return defaultPolicy, policies, nil
+}
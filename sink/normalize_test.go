@@ -0,0 +1,54 @@
+package sink
+
+import (
+	"reflect"
+	"testing"
+
+	"cppcho.com/ynab_import/parsers"
+)
+
+func TestNormalizeSink_Write(t *testing.T) {
+	out := &recordingSink{}
+	s := NormalizeSink{Sink: out, Policies: map[string]string{"shinsei": parsers.NormalizeFull}}
+
+	result := &parsers.ParseResult{ValidRecords: []parsers.Transaction{
+		{Date: "2025-12-25", Payee: "ｿｳ　ﾀｸﾍｲ", Memo: "Ｓｕｉｃａ  ﾁｬｰｼﾞ"},
+	}}
+	if _, err := s.Write(parsers.Shinsei{}, "statement.csv", result); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if got := out.written[0]; got.Payee != "ソウ タクヘイ" || got.Memo != "Suica チャージ" {
+		t.Errorf("normalized = %q, %q", got.Payee, got.Memo)
+	}
+	if result.ValidRecords[0].Payee != "ｿｳ　ﾀｸﾍｲ" {
+		t.Errorf("Write() modified the caller's records: %q", result.ValidRecords[0].Payee)
+	}
+
+	// Parsers without a policy are left as exported by default
+	out.written = nil
+	if _, err := s.Write(parsers.Smbc{}, "statement.csv", result); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if got := out.written[0]; got.Payee != "ｿｳ　ﾀｸﾍｲ" {
+		t.Errorf("smbc payee = %q, want it unchanged", got.Payee)
+	}
+}
+
+func TestParseNormalizePolicies(t *testing.T) {
+	defaultPolicy, policies, err := ParseNormalizePolicies("half, shinsei=full,suica=off")
+	if err != nil {
+		t.Fatalf("ParseNormalizePolicies() error = %v", err)
+	}
+	if defaultPolicy != "half" {
+		t.Errorf("default = %q, want half", defaultPolicy)
+	}
+	if expected := map[string]string{"shinsei": "full", "suica": "off"}; !reflect.DeepEqual(policies, expected) {
+		t.Errorf("policies = %v, want %v", policies, expected)
+	}
+
+	for _, invalid := range []string{"nfc", "shinsei=wide", "=full"} {
+		if _, _, err := ParseNormalizePolicies(invalid); err == nil {
+			t.Errorf("ParseNormalizePolicies(%q) expected error", invalid)
+		}
+	}
+}
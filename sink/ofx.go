@@ -0,0 +1,245 @@
+package sink
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"cppcho.com/ynab_import/parsers"
+)
+
+// OfxSink writes one OFX statement file per input file into OutputDir
+type OfxSink struct {
+	OutputDir  string
+	Version    int               // 1 for OFX 1.0.2 (SGML), 2 for OFX 2.2 (XML)
+	AccountIDs map[string]string // Parser name -> ACCTID, defaults to the parser name
+	Now        func() time.Time  // DTSERVER clock, for tests
+}
+
+// ofxStatement is everything one OFX statement needs
+type ofxStatement struct {
+	accountType string // AccountTypeBank or AccountTypeCreditCard
+	bankID      string
+	accountID   string
+	currency    string
+	generated   time.Time
+	records     []parsers.Transaction
+}
+
+func (s OfxSink) Write(parser parsers.Source, fileName string, result *parsers.ParseResult) (string, error) {
+	accountID := s.AccountIDs[parser.Name()]
+	if accountID == "" {
+		accountID = parser.Name()
+	}
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+
+	stmt := ofxStatement{
+		accountType: parsers.AccountTypeOf(parser),
+		bankID:      parser.Name(),
+		accountID:   accountID,
+		currency:    statementCurrency(result.ValidRecords),
+		generated:   now(),
+		records:     result.ValidRecords,
+	}
+
+	dstPath := path.Join(s.OutputDir, outputFileName(parser.Name(), fileName, ".ofx"))
+	f, err := os.Create(dstPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if err := writeOfx(f, stmt, s.Version); err != nil {
+		return "", err
+	}
+	return dstPath, nil
+}
+
+func statementCurrency(records []parsers.Transaction) string {
+	for _, record := range records {
+		if record.Amount.Currency != "" {
+			return record.Amount.Currency
+		}
+	}
+	return "JPY"
+}
+
+// ofxWriter emits OFX elements; version 1 SGML leaves aggregates closed but
+// leaf elements open, version 2 XML closes everything
+type ofxWriter struct {
+	w       io.Writer
+	version int
+	err     error
+}
+
+func (o *ofxWriter) open(tag string) {
+	o.printf("<%s>\n", tag)
+}
+
+func (o *ofxWriter) close(tag string) {
+	o.printf("</%s>\n", tag)
+}
+
+func (o *ofxWriter) leaf(tag, value string) {
+	if o.version == 1 {
+		o.printf("<%s>%s\n", tag, ofxEscape(value))
+	} else {
+		o.printf("<%s>%s</%s>\n", tag, ofxEscape(value), tag)
+	}
+}
+
+func (o *ofxWriter) printf(format string, args ...any) {
+	if o.err == nil {
+		_, o.err = fmt.Fprintf(o.w, format, args...)
+	}
+}
+
+func ofxEscape(value string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(value)
+}
+
+// ofxDate converts 2006-01-02 to the OFX 20060102 form
+func ofxDate(date string) string {
+	return strings.ReplaceAll(date, "-", "")
+}
+
+// truncateRunes shortens s to at most n characters (OFX NAME is limited to 32)
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
+
+func writeOfx(w io.Writer, stmt ofxStatement, version int) error {
+	o := &ofxWriter{w: w, version: version}
+
+	if version == 1 {
+		o.printf("OFXHEADER:100\nDATA:OFXSGML\nVERSION:102\nSECURITY:NONE\nENCODING:UNICODE\nCHARSET:NONE\nCOMPRESSION:NONE\nOLDFILEUID:NONE\nNEWFILEUID:NONE\n\n")
+	} else {
+		o.printf("<?xml version=\"1.0\" encoding=\"UTF-8\" standalone=\"no\"?>\n")
+		o.printf("<?OFX OFXHEADER=\"200\" VERSION=\"220\" SECURITY=\"NONE\" OLDFILEUID=\"NONE\" NEWFILEUID=\"NONE\"?>\n")
+	}
+
+	o.open("OFX")
+	o.open("SIGNONMSGSRSV1")
+	o.open("SONRS")
+	writeOfxStatus(o)
+	o.leaf("DTSERVER", stmt.generated.Format("20060102150405"))
+	o.leaf("LANGUAGE", "JPN")
+	o.close("SONRS")
+	o.close("SIGNONMSGSRSV1")
+
+	msgSet, trnRs, stmtRs := "BANKMSGSRSV1", "STMTTRNRS", "STMTRS"
+	if stmt.accountType == parsers.AccountTypeCreditCard {
+		msgSet, trnRs, stmtRs = "CREDITCARDMSGSRSV1", "CCSTMTTRNRS", "CCSTMTRS"
+	}
+
+	o.open(msgSet)
+	o.open(trnRs)
+	o.leaf("TRNUID", "0")
+	writeOfxStatus(o)
+	o.open(stmtRs)
+	o.leaf("CURDEF", stmt.currency)
+
+	if stmt.accountType == parsers.AccountTypeCreditCard {
+		o.open("CCACCTFROM")
+		o.leaf("ACCTID", stmt.accountID)
+		o.close("CCACCTFROM")
+	} else {
+		o.open("BANKACCTFROM")
+		o.leaf("BANKID", stmt.bankID)
+		o.leaf("ACCTID", stmt.accountID)
+		o.leaf("ACCTTYPE", "CHECKING")
+		o.close("BANKACCTFROM")
+	}
+
+	start, end := "", ""
+	for _, record := range stmt.records {
+		if record.Date == "" {
+			continue
+		}
+		if start == "" || record.Date < start {
+			start = record.Date
+		}
+		if record.Date > end {
+			end = record.Date
+		}
+	}
+	// Importers reject an empty range, so a statement without transactions
+	// covers the day it was generated
+	if start == "" {
+		start = stmt.generated.Format("2006-01-02")
+		end = start
+	}
+
+	o.open("BANKTRANLIST")
+	o.leaf("DTSTART", ofxDate(start))
+	o.leaf("DTEND", ofxDate(end))
+	for _, record := range stmt.records {
+		if record.Date == "" {
+			continue
+		}
+		trnType := "CREDIT"
+		if record.Amount.Milliunits < 0 {
+			trnType = "DEBIT"
+		}
+		o.open("STMTTRN")
+		o.leaf("TRNTYPE", trnType)
+		o.leaf("DTPOSTED", ofxDate(record.Date))
+		o.leaf("TRNAMT", record.Amount.String())
+		o.leaf("FITID", record.ImportID)
+		name := record.Description()
+		if name != "" {
+			o.leaf("NAME", truncateRunes(name, 32))
+		}
+		if memo := record.FullMemo(); memo != "" && memo != name {
+			o.leaf("MEMO", memo)
+		}
+		o.close("STMTTRN")
+	}
+	o.close("BANKTRANLIST")
+
+	balance, date := ledgerBalance(stmt, end)
+	o.open("LEDGERBAL")
+	o.leaf("BALAMT", balance.String())
+	o.leaf("DTASOF", date)
+	o.close("LEDGERBAL")
+
+	o.close(stmtRs)
+	o.close(trnRs)
+	o.close(msgSet)
+	o.close("OFX")
+
+	return o.err
+}
+
+// ledgerBalance is the closing balance of the export for LEDGERBAL, which
+// OFX requires. Exports without a running balance (card statements) get the
+// sum of their amounts as of the last transaction instead.
+func ledgerBalance(stmt ofxStatement, end string) (parsers.Money, string) {
+	if balance, date, ok := parsers.ClosingBalance(stmt.records); ok {
+		return balance, ofxDate(date)
+	}
+	sum := parsers.Money{Currency: stmt.currency}
+	for _, record := range stmt.records {
+		if record.Date != "" {
+			sum.Milliunits += record.Amount.Milliunits
+		}
+	}
+	return sum, ofxDate(end)
+}
+
+func writeOfxStatus(o *ofxWriter) {
+	o.open("STATUS")
+	o.leaf("CODE", "0")
+	o.leaf("SEVERITY", "INFO")
+	o.close("STATUS")
+}
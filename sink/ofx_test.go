@@ -0,0 +1,173 @@
+package sink
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"cppcho.com/ynab_import/parsers"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files in testdata/golden")
+
+// checkGolden compares got with testdata/golden/name, rewriting it with -update
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	goldenPath := filepath.Join("testdata", "golden", name)
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(goldenPath), 0755); err != nil {
+			t.Fatalf("MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(goldenPath, got, 0644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+	want, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run go test -update): %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s:\n%s", goldenPath, got)
+	}
+}
+
+func fixedClock() time.Time {
+	return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+}
+
+func TestWriteOfx_Golden(t *testing.T) {
+	tests := []struct {
+		parser  parsers.Parser
+		file    string
+		version int
+		golden  string
+	}{
+		{parsers.Smbc{}, "../parsers/testdata/smbc_valid.csv", 2, "smbc.ofx"},
+		{parsers.Rakuten{}, "../parsers/testdata/rakuten_valid.csv", 2, "rakuten.ofx"},
+		{parsers.Sbi{}, "../parsers/testdata/sbi_valid.csv", 1, "sbi.ofx1"},
+		{parsers.Epos{}, "../parsers/testdata/epos_valid.csv", 2, "epos.ofx"},
+		{parsers.SmbcCard{}, "../parsers/testdata/smbc_card_valid.csv", 1, "smbc_card.ofx1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			result := parseFixture(t, tt.parser, tt.file)
+			stmt := ofxStatement{
+				accountType: parsers.AccountTypeOf(tt.parser),
+				bankID:      tt.parser.Name(),
+				accountID:   "1234567",
+				currency:    "JPY",
+				generated:   fixedClock(),
+				records:     result.ValidRecords,
+			}
+
+			var buf bytes.Buffer
+			if err := writeOfx(&buf, stmt, tt.version); err != nil {
+				t.Fatalf("writeOfx() error = %v", err)
+			}
+			checkGolden(t, tt.golden, buf.Bytes())
+		})
+	}
+}
+
+func TestOfxSink_Write(t *testing.T) {
+	outputDir := t.TempDir()
+	sink := OfxSink{OutputDir: outputDir, Version: 2, AccountIDs: map[string]string{"epos": "9999"}, Now: fixedClock}
+
+	result := parseFixture(t, parsers.Epos{}, "../parsers/testdata/epos_valid.csv")
+	dest, err := sink.Write(parsers.Epos{}, "statement.csv", result)
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if dest != filepath.Join(outputDir, "epos_statement.ofx") {
+		t.Errorf("Write() = %q, want epos_statement.ofx", dest)
+	}
+
+	data, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	content := string(data)
+	for _, want := range []string{"<CCSTMTRS>", "<ACCTID>9999</ACCTID>", "<TRNAMT>-1155</TRNAMT>", "<FITID>epos:-1155000:2025-12-24:1</FITID>"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("OFX output missing %q", want)
+		}
+	}
+	// Cards have no running balance, so LEDGERBAL is the sum of the amounts
+	if want := "<LEDGERBAL>\n<BALAMT>-8855</BALAMT>\n<DTASOF>20251226</DTASOF>\n</LEDGERBAL>"; !strings.Contains(content, want) {
+		t.Errorf("OFX output missing the computed balance %q", want)
+	}
+}
+
+func TestWriteOfx_NoTransactions(t *testing.T) {
+	var buf bytes.Buffer
+	stmt := ofxStatement{bankID: "smbc", accountID: "1234567", currency: "JPY", generated: fixedClock()}
+	if err := writeOfx(&buf, stmt, 2); err != nil {
+		t.Fatalf("writeOfx() error = %v", err)
+	}
+
+	// The range and the balance fall back to the export date
+	for _, want := range []string{
+		"<DTSTART>20260102</DTSTART>",
+		"<DTEND>20260102</DTEND>",
+		"<BALAMT>0</BALAMT>",
+		"<DTASOF>20260102</DTASOF>",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("writeOfx() output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestClosingBalance(t *testing.T) {
+	oldestFirst := []parsers.Transaction{
+		{Date: "2025-11-27", Balance: parsers.Yen(1987232)},
+		{Date: "2025-11-28", Balance: parsers.Yen(2037232)},
+		{Date: "2025-11-29", Balance: parsers.Yen(2012232)},
+	}
+	newestFirst := []parsers.Transaction{
+		{Date: "2025-12-26", Balance: parsers.Yen(4257729)},
+		{Date: "2025-12-26", Balance: parsers.Yen(4226616)},
+		{Date: "2025-12-25", Balance: parsers.Yen(4231616)},
+	}
+
+	tests := []struct {
+		name     string
+		records  []parsers.Transaction
+		expected string
+		date     string
+		ok       bool
+	}{
+		{"oldest first", oldestFirst, "2012232", "2025-11-29", true},
+		{"newest first", newestFirst, "4257729", "2025-12-26", true},
+		{"no balances", []parsers.Transaction{{Date: "2025-12-26", Amount: parsers.Yen(1)}}, "", "", false},
+		{"empty", nil, "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			balance, date, ok := parsers.ClosingBalance(tt.records)
+			if ok != tt.ok {
+				t.Fatalf("parsers.ClosingBalance() ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && (balance.String() != tt.expected || date != tt.date) {
+				t.Errorf("parsers.ClosingBalance() = %s on %s, want %s on %s", balance, date, tt.expected, tt.date)
+			}
+		})
+	}
+}
+
+func TestNewFileSink(t *testing.T) {
+	for _, format := range OutputFormats {
+		if _, err := NewFileSink(format, t.TempDir(), false, false, nil); err != nil {
+			t.Errorf("NewFileSink(%q) unexpected error: %v", format, err)
+		}
+	}
+	if _, err := NewFileSink("xlsx", t.TempDir(), false, false, nil); err == nil {
+		t.Error("NewFileSink(\"xlsx\") expected error, got nil")
+	}
+}
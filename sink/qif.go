@@ -0,0 +1,53 @@
+package sink
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"cppcho.com/ynab_import/parsers"
+)
+
+// writeQif writes a QIF file with an !Account header naming the account
+func writeQif(w io.Writer, stmt textStatement) error {
+	qifType := "Bank"
+	if stmt.accountType == parsers.AccountTypeCreditCard {
+		qifType = "CCard"
+	}
+
+	if _, err := fmt.Fprintf(w, "!Account\nN%s\nT%s\n^\n!Type:%s\n", stmt.account, qifType, qifType); err != nil {
+		return err
+	}
+
+	for _, record := range stmt.records {
+		date, err := time.Parse("2006-01-02", record.Date)
+		if err != nil {
+			return fmt.Errorf("invalid date %q: %w", record.Date, err)
+		}
+		payee := record.Description()
+
+		if _, err := fmt.Fprintf(w, "D%s\nT%s\n", date.Format("01/02/2006"), record.Amount); err != nil {
+			return err
+		}
+		// No QIF field holds an ID; the check number is the one importers keep
+		if record.ImportID != "" {
+			if _, err := fmt.Fprintf(w, "N%s\n", record.ImportID); err != nil {
+				return err
+			}
+		}
+		if payee != "" {
+			if _, err := fmt.Fprintf(w, "P%s\n", oneLine(payee)); err != nil {
+				return err
+			}
+		}
+		if memo := record.FullMemo(); memo != "" && memo != payee {
+			if _, err := fmt.Fprintf(w, "M%s\n", oneLine(memo)); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprint(w, "^\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package sink
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+
+	"cppcho.com/ynab_import/parsers"
+)
+
+// RulesConfig is the top level of a rules file
+type RulesConfig struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Rule rewrites or drops records. The first rule whose conditions all match wins.
+type Rule struct {
+	Name  string     `yaml:"name"`
+	Match RuleMatch  `yaml:"match"`
+	Set   RuleAction `yaml:"set"`
+	Drop  bool       `yaml:"drop"`
+
+	payeePattern         *regexp.Regexp
+	memoPattern          *regexp.Regexp
+	minAmount, maxAmount *int64 // Milliunits
+}
+
+// RuleMatch conditions; empty fields match anything. Amounts are inclusive
+// and signed like the output (negative for outflows).
+type RuleMatch struct {
+	Parser    string `yaml:"parser"`
+	Payee     string `yaml:"payee"` // Regular expression
+	Memo      string `yaml:"memo"`  // Regular expression
+	MinAmount string `yaml:"min_amount"`
+	MaxAmount string `yaml:"max_amount"`
+}
+
+// RuleAction fields replace the record's values when non-empty. Payee and
+// memo may reference capture groups of the payee/memo pattern ($1, ${name}).
+type RuleAction struct {
+	Payee      string `yaml:"payee"`
+	Memo       string `yaml:"memo"`
+	CategoryID string `yaml:"category_id"` // YNAB category ID
+}
+
+// LoadRules reads a rules file and compiles its patterns
+func LoadRules(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var config RulesConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid rules in %s: %w", path, err)
+	}
+
+	for i := range config.Rules {
+		rule := &config.Rules[i]
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("#%d", i+1)
+		}
+		if err := rule.compile(); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", rule.Name, err)
+		}
+	}
+	return config.Rules, nil
+}
+
+// compile compiles the patterns and parses the amounts exactly, as milliunits
+func (r *Rule) compile() error {
+	var err error
+	if r.Match.Payee != "" {
+		if r.payeePattern, err = regexp.Compile(r.Match.Payee); err != nil {
+			return fmt.Errorf("invalid payee pattern: %w", err)
+		}
+	}
+	if r.Match.Memo != "" {
+		if r.memoPattern, err = regexp.Compile(r.Match.Memo); err != nil {
+			return fmt.Errorf("invalid memo pattern: %w", err)
+		}
+	}
+	if r.minAmount, err = parseRuleAmount(r.Match.MinAmount); err != nil {
+		return fmt.Errorf("invalid min_amount: %w", err)
+	}
+	if r.maxAmount, err = parseRuleAmount(r.Match.MaxAmount); err != nil {
+		return fmt.Errorf("invalid max_amount: %w", err)
+	}
+	return nil
+}
+
+func parseRuleAmount(value string) (*int64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	amount, err := parsers.ParseMoney(value, "")
+	if err != nil {
+		return nil, err
+	}
+	return &amount.Milliunits, nil
+}
+
+func (r *Rule) matches(parserName string, record parsers.Transaction) bool {
+	if r.Match.Parser != "" && r.Match.Parser != parserName {
+		return false
+	}
+	if r.payeePattern != nil && !r.payeePattern.MatchString(record.Payee) {
+		return false
+	}
+	if r.memoPattern != nil && !r.memoPattern.MatchString(record.Memo) {
+		return false
+	}
+	if r.minAmount != nil && record.Amount.Milliunits < *r.minAmount {
+		return false
+	}
+	if r.maxAmount != nil && record.Amount.Milliunits > *r.maxAmount {
+		return false
+	}
+	return true
+}
+
+func (r *Rule) apply(record parsers.Transaction) parsers.Transaction {
+	if r.Set.Payee != "" {
+		record.Payee = expandRuleTemplate(r.payeePattern, record.Payee, r.Set.Payee)
+	}
+	if r.Set.Memo != "" {
+		record.Memo = expandRuleTemplate(r.memoPattern, record.Memo, r.Set.Memo)
+	}
+	if r.Set.CategoryID != "" {
+		record.CategoryID = r.Set.CategoryID
+	}
+	return record
+}
+
+func expandRuleTemplate(pattern *regexp.Regexp, value, template string) string {
+	if pattern == nil {
+		return template
+	}
+	match := pattern.FindStringSubmatchIndex(value)
+	if match == nil {
+		return template
+	}
+	return string(pattern.ExpandString(nil, template, value, match))
+}
+
+// matchRule returns the first rule matching the record, or nil
+func matchRule(rules []Rule, parserName string, record parsers.Transaction) *Rule {
+	for i := range rules {
+		if rules[i].matches(parserName, record) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// applyRules returns the rewritten records and how many were dropped
+func applyRules(rules []Rule, parserName string, records []parsers.Transaction) ([]parsers.Transaction, int) {
+	var kept []parsers.Transaction
+	dropped := 0
+
+	for _, record := range records {
+		rule := matchRule(rules, parserName, record)
+		if rule == nil {
+			kept = append(kept, record)
+			continue
+		}
+		if rule.Drop {
+			dropped++
+			continue
+		}
+		kept = append(kept, rule.apply(record))
+	}
+	return kept, dropped
+}
+
+// RulesSink applies rules before handing records to Sink. With DryRun set it
+// only prints which rule fired for each record and writes nothing.
+type RulesSink struct {
+	Sink   Sink
+	Rules  []Rule
+	DryRun bool
+	Out    io.Writer // Dry-run report, defaults to stdout
+}
+
+func (s RulesSink) Write(parser parsers.Source, fileName string, result *parsers.ParseResult) (string, error) {
+	if s.DryRun {
+		return s.report(parser, result), nil
+	}
+
+	kept, dropped := applyRules(s.Rules, parser.Name(), result.ValidRecords)
+
+	dest, err := s.Sink.Write(parser, fileName, &parsers.ParseResult{ValidRecords: kept, SkippedRows: result.SkippedRows})
+	if err != nil {
+		return "", err
+	}
+	if dropped > 0 {
+		dest = fmt.Sprintf("%s (%d row(s) dropped by rules)", dest, dropped)
+	}
+	return dest, nil
+}
+
+func (s RulesSink) report(parser parsers.Source, result *parsers.ParseResult) string {
+	out := s.Out
+	if out == nil {
+		out = os.Stdout
+	}
+
+	dropped := 0
+	for _, record := range result.ValidRecords {
+		outcome := "no rule"
+		if rule := matchRule(s.Rules, parser.Name(), record); rule != nil {
+			if rule.Drop {
+				dropped++
+				outcome = fmt.Sprintf("rule %s: drop", rule.Name)
+			} else {
+				rewritten := rule.apply(record)
+				outcome = fmt.Sprintf("rule %s: payee=%q memo=%q", rule.Name, rewritten.Payee, rewritten.Memo)
+				if rewritten.CategoryID != "" {
+					outcome += fmt.Sprintf(" category_id=%s", rewritten.CategoryID)
+				}
+			}
+		}
+		fmt.Fprintf(out, "  %s %10s  %q -> %s\n", record.Date, record.Amount, record.Payee, outcome)
+	}
+	return fmt.Sprintf("nothing (dry run, %d row(s) would be dropped)", dropped)
+}
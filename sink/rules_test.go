@@ -0,0 +1,166 @@
+package sink
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cppcho.com/ynab_import/parsers"
+)
+
+func TestLoadRules(t *testing.T) {
+	rules, err := LoadRules("testdata/rules/rules.yaml")
+	if err != nil {
+		t.Fatalf("LoadRules() error = %v", err)
+	}
+	if len(rules) != 3 {
+		t.Fatalf("LoadRules() returned %d rules, want 3", len(rules))
+	}
+	if rules[0].Name != "drop-interest-tax" || !rules[0].Drop {
+		t.Errorf("Rule[0] = %+v", rules[0])
+	}
+}
+
+func TestLoadRules_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	if err := os.WriteFile(path, []byte("rules:\n  - match: {payee: \"([\"}\n"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if _, err := LoadRules(path); err == nil || !strings.Contains(err.Error(), "invalid payee pattern") {
+		t.Errorf("LoadRules() error = %v, want invalid pattern error", err)
+	}
+}
+
+func TestApplyRules_Shinsei(t *testing.T) {
+	rules, err := LoadRules("testdata/rules/rules.yaml")
+	if err != nil {
+		t.Fatalf("LoadRules() error = %v", err)
+	}
+	result := parseFixture(t, parsers.Shinsei{}, "../parsers/testdata/shinsei_valid.csv")
+
+	kept, dropped := applyRules(rules, "shinsei", result.ValidRecords)
+
+	// 4 tax rows dropped out of 10
+	if dropped != 4 || len(kept) != 6 {
+		t.Fatalf("applyRules() kept %d, dropped %d; want 6, 4", len(kept), dropped)
+	}
+
+	for _, record := range kept {
+		switch {
+		case strings.HasPrefix(record.Memo, "Loan"):
+			if record.Payee != "Mortgage" || record.Memo != "Loan 400956001478984" || record.CategoryID != "cat-mortgage" {
+				t.Errorf("loan record = %+v", record)
+			}
+		case strings.HasPrefix(record.Memo, "振込・振替"):
+			if record.Payee != "Salary" {
+				t.Errorf("salary record payee = %q, want %q", record.Payee, "Salary")
+			}
+		}
+	}
+}
+
+func TestRule_AmountRange(t *testing.T) {
+	yen := Rule{Match: RuleMatch{MinAmount: "-1000", MaxAmount: "-100"}}
+	dollars := Rule{Match: RuleMatch{MinAmount: "1.005", MaxAmount: "1,000.25"}}
+	for _, rule := range []*Rule{&yen, &dollars} {
+		if err := rule.compile(); err != nil {
+			t.Fatalf("compile() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		rule   *Rule
+		amount int64 // Milliunits
+		want   bool
+	}{
+		{&yen, -500000, true},
+		{&yen, -1000000, true}, // Inclusive
+		{&yen, -100000, true},
+		{&yen, -1001000, false},
+		{&yen, -99500, false},
+		{&yen, 500000, false},
+		{&dollars, 1005, true}, // Exact, not 1004 as float64 would give
+		{&dollars, 1004, false},
+		{&dollars, 1000250, true},
+		{&dollars, 1000251, false},
+	}
+	for _, tt := range tests {
+		record := parsers.Transaction{Amount: parsers.Money{Milliunits: tt.amount}}
+		if got := tt.rule.matches("smbc", record); got != tt.want {
+			t.Errorf("matches(%+v, %d milliunits) = %v, want %v", tt.rule.Match, tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestLoadRules_InvalidAmount(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	if err := os.WriteFile(path, []byte("rules:\n  - match: {min_amount: 1.0005}\n"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if _, err := LoadRules(path); err == nil || !strings.Contains(err.Error(), "invalid min_amount") {
+		t.Errorf("LoadRules() error = %v, want invalid min_amount error", err)
+	}
+}
+
+func TestRule_FirstMatchWins(t *testing.T) {
+	rules := []Rule{
+		{Name: "first", Set: RuleAction{Payee: "First"}},
+		{Name: "second", Set: RuleAction{Payee: "Second"}},
+	}
+	kept, _ := applyRules(rules, "smbc", []parsers.Transaction{{Payee: "x", Amount: parsers.Yen(1)}})
+	if kept[0].Payee != "First" {
+		t.Errorf("payee = %q, want %q", kept[0].Payee, "First")
+	}
+}
+
+func TestRulesSink_DryRun(t *testing.T) {
+	rules, err := LoadRules("testdata/rules/rules.yaml")
+	if err != nil {
+		t.Fatalf("LoadRules() error = %v", err)
+	}
+
+	inner := &recordingSink{}
+	var out bytes.Buffer
+	sink := RulesSink{Sink: inner, Rules: rules, DryRun: true, Out: &out}
+
+	result := &parsers.ParseResult{ValidRecords: []parsers.Transaction{
+		{Date: "2026-01-01", Memo: "国税", Amount: parsers.Yen(-11)},
+		{Date: "2025-12-26", Memo: "ローン振替返済-400956001478984", Amount: parsers.Yen(-98944)},
+		{Date: "2025-12-27", Payee: "Other", Amount: parsers.Yen(-1)},
+	}}
+	dest, err := sink.Write(parsers.Shinsei{}, "shinsei.csv", result)
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	if len(inner.written) != 0 {
+		t.Errorf("dry run wrote %d records, want 0", len(inner.written))
+	}
+	if !strings.Contains(dest, "1 row(s) would be dropped") {
+		t.Errorf("Write() = %q", dest)
+	}
+
+	report := out.String()
+	for _, want := range []string{"rule drop-interest-tax: drop", `rule loan: payee="Mortgage" memo="Loan 400956001478984" category_id=cat-mortgage`, "no rule"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("dry-run report missing %q:\n%s", want, report)
+		}
+	}
+}
+
+func TestRulesSink_Write(t *testing.T) {
+	rules := []Rule{{Name: "drop-all", Drop: true, Match: RuleMatch{Parser: "smbc"}}}
+	inner := &recordingSink{}
+	sink := RulesSink{Sink: inner, Rules: rules}
+
+	result := &parsers.ParseResult{ValidRecords: []parsers.Transaction{{Date: "2025-12-26", Amount: parsers.Yen(1)}}}
+	dest, err := sink.Write(parsers.Smbc{}, "smbc.csv", result)
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if len(inner.written) != 0 || dest != "memory (1 row(s) dropped by rules)" {
+		t.Errorf("Write() = %q with %d records", dest, len(inner.written))
+	}
+}
@@ -0,0 +1,225 @@
+// Package sink writes parsed transactions to files or the YNAB API
+package sink
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"cppcho.com/ynab_import/parsers"
+)
+
+// OutputFormats are the formats NewFileSink accepts
+var OutputFormats = []string{"csv", "ofx", "ofx1", "qif", "hledger", "beancount"}
+
+// NewFileSink returns the sink writing the given format into outputDir.
+// accounts maps parser names to the account ID (OFX) or account name
+// (QIF, hledger, beancount) for formats that need one. openAccounts adds
+// open directives to beancount files.
+func NewFileSink(format, outputDir string, fxColumns, openAccounts bool, accounts map[string]string) (Sink, error) {
+	switch format {
+	case "csv":
+		return CsvSink{OutputDir: outputDir, FXColumns: fxColumns}, nil
+	case "ofx":
+		return OfxSink{OutputDir: outputDir, Version: 2, AccountIDs: accounts}, nil
+	case "ofx1":
+		return OfxSink{OutputDir: outputDir, Version: 1, AccountIDs: accounts}, nil
+	case "qif":
+		return TextSink{OutputDir: outputDir, Ext: ".qif", Accounts: accounts, Render: writeQif}, nil
+	case "hledger":
+		return TextSink{OutputDir: outputDir, Ext: ".journal", Accounts: accounts, Render: writeHledger}, nil
+	case "beancount":
+		render := writeBeancount
+		if openAccounts {
+			render = writeBeancountWithOpens
+		}
+		return TextSink{OutputDir: outputDir, Ext: ".beancount", Accounts: accounts, Render: render}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q (want one of %s)", format, strings.Join(OutputFormats, ", "))
+	}
+}
+
+// Sink receives the parsed records of one matched input file
+type Sink interface {
+	// Write delivers the result and returns a description of where it went
+	Write(parser parsers.Source, fileName string, result *parsers.ParseResult) (string, error)
+}
+
+// StreamSink is a Sink that can also write the records of a file one at a
+// time as they are parsed, so large exports are never held in memory.
+// Sinks that need the whole file (sorting, OFX, the ledger, uploads) only
+// implement Sink.
+type StreamSink interface {
+	Sink
+	Stream(parser parsers.Source, fileName string) (RecordWriter, error)
+}
+
+// RecordWriter receives the records of one file from a StreamSink
+type RecordWriter interface {
+	WriteRecord(record parsers.Transaction) error
+	// Close finishes the file and returns a description of where it went
+	Close() (string, error)
+	// Abort drops what was written when parsing fails midway
+	Abort() error
+}
+
+// Streaming returns out as a StreamSink when it and every sink it wraps can
+// stream
+func Streaming(out Sink) (StreamSink, bool) {
+	if normalize, ok := out.(NormalizeSink); ok {
+		if _, ok := Streaming(normalize.Sink); !ok {
+			return nil, false
+		}
+	}
+	streaming, ok := out.(StreamSink)
+	return streaming, ok
+}
+
+// CsvSink writes YNAB CSV files into OutputDir
+type CsvSink struct {
+	OutputDir string
+	FXColumns bool // Foreign currency details as extra columns instead of in the memo
+}
+
+func (s CsvSink) Write(parser parsers.Source, fileName string, result *parsers.ParseResult) (string, error) {
+	dstPath := path.Join(s.OutputDir, outputFileName(parser.Name(), fileName, ".csv"))
+	write := writeRecordsToCsv
+	if s.FXColumns {
+		write = writeRecordsToCsvWithFX
+	}
+	if err := write(result.ValidRecords, dstPath); err != nil {
+		return "", err
+	}
+	return dstPath, nil
+}
+
+func (s CsvSink) Stream(parser parsers.Source, fileName string) (RecordWriter, error) {
+	dstPath := path.Join(s.OutputDir, outputFileName(parser.Name(), fileName, ".csv"))
+	w, err := createCsv(dstPath, s.FXColumns)
+	if err != nil {
+		return nil, err
+	}
+	return csvRecordWriter{csvWriter: w, path: dstPath}, nil
+}
+
+type csvRecordWriter struct {
+	*csvWriter
+	path string
+}
+
+func (w csvRecordWriter) WriteRecord(record parsers.Transaction) error {
+	return w.write(record)
+}
+
+func (w csvRecordWriter) Close() (string, error) {
+	if err := w.close(); err != nil {
+		return "", err
+	}
+	return w.path, nil
+}
+
+func (w csvRecordWriter) Abort() error {
+	w.close()
+	return os.Remove(w.path)
+}
+
+// outputFileName builds "{parser}_{source name}{ext}" (e.g. smbc_statement.csv)
+func outputFileName(parserName, fileName, ext string) string {
+	baseName := strings.TrimSuffix(fileName, path.Ext(fileName))
+	return parserName + "_" + baseName + ext
+}
+
+// textStatement is the input of a plain-text accounting writer
+type textStatement struct {
+	account     string // e.g. Assets:Bank:SMBC
+	accountType string // AccountTypeBank or AccountTypeCreditCard
+	records     []parsers.Transaction
+}
+
+// statementRenderer writes one statement in a plain-text accounting format
+type statementRenderer func(w io.Writer, stmt textStatement) error
+
+// TextSink writes one file per input file with a statementRenderer
+type TextSink struct {
+	OutputDir string
+	Ext       string
+	Accounts  map[string]string // Parser name -> account name, defaults to defaultAccountName
+	Render    statementRenderer
+}
+
+func (s TextSink) Write(parser parsers.Source, fileName string, result *parsers.ParseResult) (string, error) {
+	account := s.Accounts[parser.Name()]
+	if account == "" {
+		account = defaultAccountName(parser)
+	}
+
+	stmt := textStatement{
+		account:     account,
+		accountType: parsers.AccountTypeOf(parser),
+		records:     chronological(result.ValidRecords),
+	}
+
+	dstPath := path.Join(s.OutputDir, outputFileName(parser.Name(), fileName, s.Ext))
+	f, err := os.Create(dstPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if err := s.Render(f, stmt); err != nil {
+		return "", err
+	}
+	return dstPath, nil
+}
+
+// defaultAccountName derives an account from the parser, e.g.
+// Assets:Bank:Smbc or Liabilities:Card:RakutenCard
+func defaultAccountName(parser parsers.Source) string {
+	var name strings.Builder
+	for _, part := range strings.Split(parser.Name(), "_") {
+		if part != "" {
+			name.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	if parsers.AccountTypeOf(parser) == parsers.AccountTypeCreditCard {
+		return "Liabilities:Card:" + name.String()
+	}
+	return "Assets:Bank:" + name.String()
+}
+
+// counterAccount is the other side of a posting, since exports carry no category
+func counterAccount(amount parsers.Money) string {
+	if amount.Milliunits > 0 {
+		return "Income:Uncategorized"
+	}
+	return "Expenses:Uncategorized"
+}
+
+// chronological returns the dated records oldest first, keeping the
+// export order within a day
+func chronological(records []parsers.Transaction) []parsers.Transaction {
+	var dated []parsers.Transaction
+	for _, record := range records {
+		if record.Date != "" {
+			dated = append(dated, record)
+		}
+	}
+	// Newest-first exports are reversed so same-day rows stay in booking order
+	if len(dated) > 1 && dated[0].Date > dated[len(dated)-1].Date {
+		for i, j := 0, len(dated)-1; i < j; i, j = i+1, j-1 {
+			dated[i], dated[j] = dated[j], dated[i]
+		}
+	}
+	sort.SliceStable(dated, func(i, j int) bool {
+		return dated[i].Date < dated[j].Date
+	})
+	return dated
+}
+
+// oneLine flattens a field for line-based formats, keeping full-width spaces
+func oneLine(value string) string {
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ", "\t", " ").Replace(value)
+}
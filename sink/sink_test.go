@@ -0,0 +1,131 @@
+package sink
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cppcho.com/ynab_import/encoding"
+	"cppcho.com/ynab_import/parsers"
+)
+
+// Every built-in CSV fixture from the parser tests, for golden-file tests of
+// the output writers
+var fixtureParsers = []struct {
+	parser parsers.Parser
+	file   string
+}{
+	{parsers.Smbc{}, "../parsers/testdata/smbc_valid.csv"},
+	{parsers.Rakuten{}, "../parsers/testdata/rakuten_valid.csv"},
+	{parsers.Sbi{}, "../parsers/testdata/sbi_valid.csv"},
+	{parsers.Shinsei{}, "../parsers/testdata/shinsei_valid.csv"},
+	{parsers.Epos{}, "../parsers/testdata/epos_valid.csv"},
+	{parsers.SmbcCard{}, "../parsers/testdata/smbc_card_valid.csv"},
+	{parsers.PayPay{}, "../parsers/testdata/paypay_valid.csv"},
+}
+
+// parseFixture parses a testdata file, which also assigns import IDs
+func parseFixture(t *testing.T, parser parsers.Parser, file string) *parsers.ParseResult {
+	t.Helper()
+	records, err := encoding.ReadCSVFile(file)
+	if err != nil {
+		t.Fatalf("encoding.ReadCSVFile() error = %v", err)
+	}
+	result, err := parsers.Parse(parser, records)
+	if err != nil || result == nil {
+		t.Fatalf("Parse() = %v, %v", result, err)
+	}
+	return result
+}
+
+func TestTextSink_Golden(t *testing.T) {
+	accounts := map[string]string{"smbc": "Assets:Bank:SMBC"}
+
+	for _, format := range []string{"qif", "hledger", "beancount"} {
+		for _, fixture := range fixtureParsers {
+			name := fixture.parser.Name() + "." + format
+			t.Run(name, func(t *testing.T) {
+				outputDir := t.TempDir()
+				sink, err := NewFileSink(format, outputDir, false, false, accounts)
+				if err != nil {
+					t.Fatalf("NewFileSink() error = %v", err)
+				}
+
+				result := parseFixture(t, fixture.parser, fixture.file)
+				dest, err := sink.Write(fixture.parser, filepath.Base(fixture.file), result)
+				if err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+				got, err := os.ReadFile(dest)
+				if err != nil {
+					t.Fatalf("ReadFile() error = %v", err)
+				}
+				checkGolden(t, name, got)
+			})
+		}
+	}
+}
+
+func TestDefaultAccountName(t *testing.T) {
+	tests := []struct {
+		parser   parsers.Parser
+		expected string
+	}{
+		{parsers.Smbc{}, "Assets:Bank:Smbc"},
+		{parsers.RakutenCard{}, "Liabilities:Card:RakutenCard"},
+		{parsers.SmbcCard2{}, "Liabilities:Card:SmbcCard2"},
+	}
+
+	for _, tt := range tests {
+		if got := defaultAccountName(tt.parser); got != tt.expected {
+			t.Errorf("defaultAccountName(%s) = %q, want %q", tt.parser.Name(), got, tt.expected)
+		}
+	}
+}
+
+func TestChronological(t *testing.T) {
+	records := []parsers.Transaction{
+		{Date: "2025-12-26", Payee: "C"},
+		{Date: "2025-12-26", Payee: "B"},
+		{Date: "", Payee: "no date"},
+		{Date: "2025-12-25", Payee: "A"},
+	}
+
+	var payees []string
+	for _, record := range chronological(records) {
+		payees = append(payees, record.Payee)
+	}
+	if got := strings.Join(payees, ","); got != "A,B,C" {
+		t.Errorf("chronological() order = %s, want A,B,C", got)
+	}
+}
+
+func TestBeancountString(t *testing.T) {
+	if got := beancountString("Say \"hi\"\\\nthere"); got != `"Say \"hi\"\\ there"` {
+		t.Errorf("beancountString() = %s", got)
+	}
+}
+
+func TestWriteBeancountWithOpens(t *testing.T) {
+	stmt := textStatement{account: "Assets:Bank:SMBC", records: []parsers.Transaction{
+		{Date: "2025-12-25", Payee: "入金テスト", Amount: parsers.Yen(5000), ImportID: "smbc:5000000:2025-12-25:1"},
+		{Date: "2025-12-26", Payee: "テスト支払", Amount: parsers.Yen(-23000), ImportID: "smbc:-23000000:2025-12-26:1"},
+	}}
+
+	var withOpens, without strings.Builder
+	if err := writeBeancountWithOpens(&withOpens, stmt); err != nil {
+		t.Fatalf("writeBeancountWithOpens() error = %v", err)
+	}
+	if err := writeBeancount(&without, stmt); err != nil {
+		t.Fatalf("writeBeancount() error = %v", err)
+	}
+
+	opens := "2025-12-25 open Assets:Bank:SMBC\n2025-12-25 open Expenses:Uncategorized\n2025-12-25 open Income:Uncategorized\n\n"
+	if got := withOpens.String(); got != opens+without.String() {
+		t.Errorf("writeBeancountWithOpens() =\n%s\nwant the opens before\n%s", got, without.String())
+	}
+	if strings.Contains(without.String(), " open ") {
+		t.Errorf("writeBeancount() opens accounts:\n%s", without.String())
+	}
+}
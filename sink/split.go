@@ -0,0 +1,69 @@
+package sink
+
+import (
+	"fmt"
+	"strings"
+
+	"cppcho.com/ynab_import/parsers"
+)
+
+// SplitSink separates exports that mix several accounts, like SMBC Card
+// family cards or PayPay family members. Accounts maps "parser:key" (e.g.
+// smbc_card:family) to the account name the records are written under;
+// that name replaces the parser name in output files and in the -accounts
+// and -ynab-accounts mappings. Records of unmapped keys stay with the parser.
+type SplitSink struct {
+	Sink     Sink
+	Accounts map[string]string
+}
+
+// splitAccount is one account of a split export
+type splitAccount struct {
+	parser parsers.Source
+	name   string
+}
+
+func (a splitAccount) Name() string {
+	return a.name
+}
+
+func (a splitAccount) AccountType() string {
+	return parsers.AccountTypeOf(a.parser)
+}
+
+func (s SplitSink) Write(parser parsers.Source, fileName string, result *parsers.ParseResult) (string, error) {
+	// Group by account name, in order of first appearance
+	var names []string
+	groups := map[string][]parsers.Transaction{}
+	for _, record := range result.ValidRecords {
+		name := parser.Name()
+		if mapped := s.Accounts[parser.Name()+":"+record.Account]; record.Account != "" && mapped != "" {
+			name = mapped
+		}
+		if _, ok := groups[name]; !ok {
+			names = append(names, name)
+		}
+		groups[name] = append(groups[name], record)
+	}
+	if len(names) == 0 || (len(names) == 1 && names[0] == parser.Name()) {
+		return s.Sink.Write(parser, fileName, result)
+	}
+
+	var dests []string
+	for i, name := range names {
+		part := &parsers.ParseResult{ValidRecords: groups[name]}
+		if i == 0 {
+			part.SkippedRows = result.SkippedRows // Report them once
+		}
+		var account parsers.Source = splitAccount{parser: parser, name: name}
+		if name == parser.Name() {
+			account = parser
+		}
+		dest, err := s.Sink.Write(account, fileName, part)
+		if err != nil {
+			return "", fmt.Errorf("account %s: %w", name, err)
+		}
+		dests = append(dests, dest)
+	}
+	return strings.Join(dests, ", "), nil
+}
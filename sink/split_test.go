@@ -0,0 +1,78 @@
+package sink
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cppcho.com/ynab_import/parsers"
+)
+
+func TestSplitSink_Write(t *testing.T) {
+	outputDir := t.TempDir()
+	split := SplitSink{
+		Sink:     CsvSink{OutputDir: outputDir},
+		Accounts: map[string]string{"smbc_card:self": "smbc_card_self", "smbc_card:family": "smbc_card_family"},
+	}
+
+	result := parseFixture(t, parsers.SmbcCard{}, "../parsers/testdata/smbc_card_valid.csv")
+	dest, err := split.Write(parsers.SmbcCard{}, "statement.csv", result)
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	want := filepath.Join(outputDir, "smbc_card_self_statement.csv") + ", " + filepath.Join(outputDir, "smbc_card_family_statement.csv")
+	if dest != want {
+		t.Errorf("Write() = %q, want %q", dest, want)
+	}
+
+	// Header plus the rows of each holder
+	for file, rows := range map[string]int{"smbc_card_self_statement.csv": 3, "smbc_card_family_statement.csv": 2} {
+		data, err := os.ReadFile(filepath.Join(outputDir, file))
+		if err != nil {
+			t.Fatalf("ReadFile() error = %v", err)
+		}
+		if got := strings.Count(string(data), "\n"); got != rows {
+			t.Errorf("%s has %d line(s), want %d", file, got, rows)
+		}
+	}
+}
+
+func TestSplitSink_Write_Unmapped(t *testing.T) {
+	// Only the family card is split, the holder's rows keep the parser name
+	out := &namingSink{}
+	split := SplitSink{Sink: out, Accounts: map[string]string{"smbc_card:family": "smbc_card_family"}}
+
+	result := parseFixture(t, parsers.SmbcCard{}, "../parsers/testdata/smbc_card_valid.csv")
+	if _, err := split.Write(parsers.SmbcCard{}, "statement.csv", result); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if got := strings.Join(out.names, ","); got != "smbc_card,smbc_card_family" {
+		t.Errorf("written accounts = %s, want smbc_card,smbc_card_family", got)
+	}
+	if out.types[1] != parsers.AccountTypeCreditCard {
+		t.Errorf("split account type = %q, want %q", out.types[1], parsers.AccountTypeCreditCard)
+	}
+
+	// Other parsers pass through unchanged
+	out.names = nil
+	result = parseFixture(t, parsers.Smbc{}, "../parsers/testdata/smbc_valid.csv")
+	if _, err := split.Write(parsers.Smbc{}, "statement.csv", result); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if got := strings.Join(out.names, ","); got != "smbc" {
+		t.Errorf("written accounts = %s, want smbc", got)
+	}
+}
+
+// namingSink records the account each write went to
+type namingSink struct {
+	names []string
+	types []string
+}
+
+func (s *namingSink) Write(parser parsers.Source, fileName string, result *parsers.ParseResult) (string, error) {
+	s.names = append(s.names, parser.Name())
+	s.types = append(s.types, parsers.AccountTypeOf(parser))
+	return parser.Name(), nil
+}
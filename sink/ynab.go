@@ -0,0 +1,300 @@
+package sink
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"cppcho.com/ynab_import/parsers"
+)
+
+// DefaultYnabBaseURL is the production YNAB API
+const DefaultYnabBaseURL = "https://api.ynab.com/v1"
+
+// YnabSink uploads records to the YNAB API instead of writing files
+type YnabSink struct {
+	BaseURL    string            // e.g. https://api.ynab.com/v1 or a local stub server
+	Token      string            // Personal access token
+	BudgetID   string            // Budget ID or "last-used"
+	AccountIDs map[string]string // Parser name -> YNAB account ID
+	Client     *http.Client
+	Now        func() time.Time // Defaults to time.Now, records after today are scheduled
+}
+
+type ynabTransaction struct {
+	AccountID  string `json:"account_id"`
+	Date       string `json:"date"`
+	Amount     int64  `json:"amount"`
+	PayeeName  string `json:"payee_name,omitempty"`
+	Memo       string `json:"memo,omitempty"`
+	CategoryID string `json:"category_id,omitempty"`
+	Cleared    string `json:"cleared"`
+	Approved   bool   `json:"approved"`
+	ImportID   string `json:"import_id"`
+}
+
+// ynabScheduledTransaction is a one-off scheduled transaction. They have no
+// import ID, so the ones already scheduled are found by account, date,
+// amount and memo (e.g. "installment 2/3") instead.
+type ynabScheduledTransaction struct {
+	AccountID  string `json:"account_id"`
+	Date       string `json:"date"`
+	Amount     int64  `json:"amount"`
+	PayeeName  string `json:"payee_name,omitempty"`
+	Memo       string `json:"memo,omitempty"`
+	CategoryID string `json:"category_id,omitempty"`
+	Frequency  string `json:"frequency"`
+}
+
+type ynabScheduledTransactionRequest struct {
+	ScheduledTransaction ynabScheduledTransaction `json:"scheduled_transaction"`
+}
+
+// ynabScheduledTransactionDetail is a scheduled transaction of the budget.
+// A one-off is due on date_first until YNAB enters it.
+type ynabScheduledTransactionDetail struct {
+	AccountID string `json:"account_id"`
+	DateFirst string `json:"date_first"`
+	Amount    int64  `json:"amount"`
+	Memo      string `json:"memo"`
+	Deleted   bool   `json:"deleted"`
+}
+
+type ynabScheduledTransactionsResponse struct {
+	Data struct {
+		ScheduledTransactions []ynabScheduledTransactionDetail `json:"scheduled_transactions"`
+	} `json:"data"`
+}
+
+type ynabTransactionsRequest struct {
+	Transactions []ynabTransaction `json:"transactions"`
+}
+
+type ynabTransactionsResponse struct {
+	Data struct {
+		TransactionIDs     []string `json:"transaction_ids"`
+		DuplicateImportIDs []string `json:"duplicate_import_ids"`
+	} `json:"data"`
+}
+
+type ynabErrorResponse struct {
+	Error struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Detail string `json:"detail"`
+	} `json:"error"`
+}
+
+func (s YnabSink) Write(parser parsers.Source, fileName string, result *parsers.ParseResult) (string, error) {
+	accountID, ok := s.AccountIDs[parser.Name()]
+	if !ok || accountID == "" {
+		return "", fmt.Errorf("no YNAB account mapped for parser %s", parser.Name())
+	}
+
+	// YNAB rejects future transactions, upcoming installments are scheduled instead
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	today := now().Format("2006-01-02")
+	var current, upcoming []parsers.Transaction
+	for _, record := range result.ValidRecords {
+		if record.Date > today {
+			upcoming = append(upcoming, record)
+		} else {
+			current = append(current, record)
+		}
+	}
+
+	transactions := buildYnabTransactions(accountID, current)
+	if len(transactions) == 0 && len(upcoming) == 0 {
+		return fmt.Sprintf("YNAB account %s (nothing to upload)", accountID), nil
+	}
+
+	var created ynabTransactionsResponse
+	if len(transactions) > 0 {
+		if err := s.post("/transactions", ynabTransactionsRequest{Transactions: transactions}, &created); err != nil {
+			return "", err
+		}
+	}
+	newScheduled, alreadyScheduled, err := s.schedule(buildYnabScheduledTransactions(accountID, upcoming))
+	if err != nil {
+		return "", err
+	}
+
+	summary := fmt.Sprintf("YNAB account %s (%d created, %d duplicate",
+		accountID, len(created.Data.TransactionIDs), len(created.Data.DuplicateImportIDs))
+	if newScheduled > 0 {
+		summary += fmt.Sprintf(", %d scheduled", newScheduled)
+	}
+	if alreadyScheduled > 0 {
+		summary += fmt.Sprintf(", %d already scheduled", alreadyScheduled)
+	}
+	return summary + ")", nil
+}
+
+// schedule posts the scheduled transactions that the budget doesn't have
+// yet, so uploading an export again (or watch mode parsing it again) never
+// schedules an installment twice
+func (s YnabSink) schedule(scheduled []ynabScheduledTransaction) (int, int, error) {
+	if len(scheduled) == 0 {
+		return 0, 0, nil
+	}
+
+	var existing ynabScheduledTransactionsResponse
+	if err := s.request(http.MethodGet, "/scheduled_transactions", nil, &existing); err != nil {
+		return 0, 0, fmt.Errorf("failed to list scheduled transactions: %w", err)
+	}
+	type key struct {
+		accountID, date, memo string
+		amount                int64
+	}
+	seen := map[key]bool{}
+	for _, e := range existing.Data.ScheduledTransactions {
+		if !e.Deleted {
+			seen[key{e.AccountID, e.DateFirst, e.Memo, e.Amount}] = true
+		}
+	}
+
+	posted := 0
+	for _, transaction := range scheduled {
+		k := key{transaction.AccountID, transaction.Date, transaction.Memo, transaction.Amount}
+		if seen[k] {
+			continue
+		}
+		if err := s.post("/scheduled_transactions", ynabScheduledTransactionRequest{ScheduledTransaction: transaction}, nil); err != nil {
+			return posted, 0, fmt.Errorf("failed to schedule %s %s: %w", transaction.Date, transaction.PayeeName, err)
+		}
+		seen[k] = true
+		posted++
+	}
+	return posted, len(scheduled) - posted, nil
+}
+
+// post sends body to a budget endpoint and decodes the response into out
+// unless it is nil
+func (s YnabSink) post(endpoint string, body, out any) error {
+	return s.request(http.MethodPost, endpoint, body, out)
+}
+
+// request calls a budget endpoint, with body as JSON unless it is nil
+func (s YnabSink) request(method, endpoint string, body, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	baseURL := s.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultYnabBaseURL
+	}
+	budgetID := s.BudgetID
+	if budgetID == "" {
+		budgetID = "last-used"
+	}
+	url := strings.TrimSuffix(baseURL, "/") + "/budgets/" + budgetID + endpoint
+
+	req, err := http.NewRequest(method, url, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.Token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("YNAB request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read YNAB response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		var apiErr ynabErrorResponse
+		if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Error.Detail != "" {
+			return fmt.Errorf("YNAB API returned %d: %s", resp.StatusCode, apiErr.Error.Detail)
+		}
+		return fmt.Errorf("YNAB API returned %d", resp.StatusCode)
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("failed to decode YNAB response: %w", err)
+		}
+	}
+	return nil
+}
+
+func buildYnabTransactions(accountID string, records []parsers.Transaction) []ynabTransaction {
+	var transactions []ynabTransaction
+
+	for _, record := range records {
+		// Same rule as writeRecordsToCsv
+		if record.Date == "" {
+			continue
+		}
+
+		transactions = append(transactions, ynabTransaction{
+			AccountID:  accountID,
+			Date:       record.Date,
+			Amount:     record.Amount.Milliunits,
+			PayeeName:  record.Payee,
+			Memo:       record.FullMemo(),
+			CategoryID: record.CategoryID,
+			Cleared:    "uncleared",
+			Approved:   false,
+			ImportID:   record.ImportID,
+		})
+	}
+	return transactions
+}
+
+func buildYnabScheduledTransactions(accountID string, records []parsers.Transaction) []ynabScheduledTransaction {
+	var scheduled []ynabScheduledTransaction
+	for _, transaction := range buildYnabTransactions(accountID, records) {
+		scheduled = append(scheduled, ynabScheduledTransaction{
+			AccountID:  transaction.AccountID,
+			Date:       transaction.Date,
+			Amount:     transaction.Amount,
+			PayeeName:  transaction.PayeeName,
+			Memo:       transaction.Memo,
+			CategoryID: transaction.CategoryID,
+			Frequency:  "never",
+		})
+	}
+	return scheduled
+}
+
+// ParseAccountMap parses "smbc=ACCOUNT_ID,rakuten=ACCOUNT_ID" into a map
+func ParseAccountMap(value string) (map[string]string, error) {
+	accounts := map[string]string{}
+	if strings.TrimSpace(value) == "" {
+		return accounts, nil
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		name, id, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || name == "" || id == "" {
+			return nil, fmt.Errorf("invalid account mapping %q (want parser=account_id)", pair)
+		}
+		accounts[name] = id
+	}
+	return accounts, nil
+}
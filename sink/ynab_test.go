@@ -0,0 +1,196 @@
+package sink
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"cppcho.com/ynab_import/parsers"
+)
+
+func TestParseAccountMap(t *testing.T) {
+	accounts, err := ParseAccountMap("smbc=aaa, rakuten_card=bbb")
+	if err != nil {
+		t.Fatalf("ParseAccountMap() unexpected error: %v", err)
+	}
+	if accounts["smbc"] != "aaa" || accounts["rakuten_card"] != "bbb" {
+		t.Errorf("ParseAccountMap() = %v", accounts)
+	}
+
+	if accounts, err := ParseAccountMap(""); err != nil || len(accounts) != 0 {
+		t.Errorf("ParseAccountMap(\"\") = %v, %v; want empty map", accounts, err)
+	}
+
+	if _, err := ParseAccountMap("smbc"); err == nil {
+		t.Error("ParseAccountMap(\"smbc\") expected error, got nil")
+	}
+}
+
+func TestYnabSink_Write(t *testing.T) {
+	var gotPath, gotAuth string
+	var gotRequest ynabTransactionsRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&gotRequest); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"data":{"transaction_ids":["t1","t2"],"duplicate_import_ids":["smbc:-500000:2024-01-16:1"]}}`))
+	}))
+	defer server.Close()
+
+	sink := YnabSink{
+		BaseURL:    server.URL,
+		Token:      "secret",
+		BudgetID:   "budget-1",
+		AccountIDs: map[string]string{"smbc": "account-1"},
+	}
+
+	result := &parsers.ParseResult{ValidRecords: []parsers.Transaction{
+		{Date: "2024-01-15", Payee: "Store A", Amount: parsers.Yen(1000)},
+		{Date: "2024-01-16", Payee: "Store B", Memo: "Memo", Amount: parsers.Yen(-500)},
+		{Date: "2024-01-16", Payee: "Store B", Memo: "Memo", Amount: parsers.Yen(-500)},
+		{Date: "", Payee: "No Date", Amount: parsers.Yen(100)}, // Skipped like the CSV writer
+	}}
+	parsers.AssignImportIDs("smbc", result.ValidRecords)
+
+	dest, err := sink.Write(parsers.Smbc{}, "statement.csv", result)
+	if err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+
+	if gotPath != "/budgets/budget-1/transactions" {
+		t.Errorf("request path = %q, want %q", gotPath, "/budgets/budget-1/transactions")
+	}
+	if gotAuth != "Bearer secret" {
+		t.Errorf("Authorization = %q, want %q", gotAuth, "Bearer secret")
+	}
+	if !strings.Contains(dest, "2 created, 1 duplicate") {
+		t.Errorf("Write() = %q, want created/duplicate counts", dest)
+	}
+
+	if len(gotRequest.Transactions) != 3 {
+		t.Fatalf("sent %d transactions, want 3", len(gotRequest.Transactions))
+	}
+
+	first := gotRequest.Transactions[0]
+	if first.AccountID != "account-1" || first.Amount != 1000000 || first.Date != "2024-01-15" || first.PayeeName != "Store A" {
+		t.Errorf("Transaction[0] = %+v", first)
+	}
+
+	if gotRequest.Transactions[2].ImportID != "smbc:-500000:2024-01-16:2" {
+		t.Errorf("Transaction[2].ImportID = %q, want %q", gotRequest.Transactions[2].ImportID, "smbc:-500000:2024-01-16:2")
+	}
+}
+
+func TestYnabSink_Write_UnmappedParser(t *testing.T) {
+	sink := YnabSink{BaseURL: "http://127.0.0.1:0", AccountIDs: map[string]string{}}
+
+	result := &parsers.ParseResult{ValidRecords: []parsers.Transaction{{Date: "2024-01-15", Amount: parsers.Yen(1000)}}}
+	if _, err := sink.Write(parsers.Smbc{}, "statement.csv", result); err == nil {
+		t.Error("Write() expected error for unmapped parser, got nil")
+	}
+}
+
+func TestYnabSink_Write_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":{"id":"401","name":"unauthorized","detail":"Unauthorized"}}`))
+	}))
+	defer server.Close()
+
+	sink := YnabSink{BaseURL: server.URL, AccountIDs: map[string]string{"smbc": "account-1"}}
+
+	result := &parsers.ParseResult{ValidRecords: []parsers.Transaction{{Date: "2024-01-15", Amount: parsers.Yen(1000)}}}
+	_, err := sink.Write(parsers.Smbc{}, "statement.csv", result)
+	if err == nil || !strings.Contains(err.Error(), "Unauthorized") {
+		t.Errorf("Write() error = %v, want API error detail", err)
+	}
+}
+
+func TestYnabSink_Write_SchedulesFutureRecords(t *testing.T) {
+	var requests []string
+	var scheduled []ynabScheduledTransaction
+
+	// Lists what was scheduled so far, like the API
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if strings.HasSuffix(r.URL.Path, "/scheduled_transactions") {
+			if r.Method == http.MethodGet {
+				var response ynabScheduledTransactionsResponse
+				for _, transaction := range scheduled {
+					response.Data.ScheduledTransactions = append(response.Data.ScheduledTransactions, ynabScheduledTransactionDetail{
+						AccountID: transaction.AccountID,
+						DateFirst: transaction.Date,
+						Amount:    transaction.Amount,
+						Memo:      transaction.Memo,
+					})
+				}
+				json.NewEncoder(w).Encode(response)
+				return
+			}
+			var request ynabScheduledTransactionRequest
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			scheduled = append(scheduled, request.ScheduledTransaction)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"data":{"transaction_ids":["t1"],"duplicate_import_ids":[]}}`))
+	}))
+	defer server.Close()
+
+	sink := YnabSink{
+		BaseURL:    server.URL,
+		BudgetID:   "budget-1",
+		AccountIDs: map[string]string{"smbc_card": "account-1"},
+		Now:        func() time.Time { return time.Date(2026, 1, 23, 12, 0, 0, 0, time.UTC) },
+	}
+
+	result := &parsers.ParseResult{ValidRecords: []parsers.Transaction{
+		{Date: "2026-01-23", Payee: "家電店", Memo: "installment 1/3", Amount: parsers.Yen(-15000)},
+		{Date: "2026-02-23", Payee: "家電店", Memo: "installment 2/3", Amount: parsers.Yen(-15000)},
+		{Date: "2026-03-23", Payee: "家電店", Memo: "installment 3/3", Amount: parsers.Yen(-15000)},
+	}}
+	dest, err := sink.Write(parsers.SmbcCard{}, "statement.csv", result)
+	if err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+
+	expectedRequests := []string{
+		"POST /budgets/budget-1/transactions",
+		"GET /budgets/budget-1/scheduled_transactions",
+		"POST /budgets/budget-1/scheduled_transactions",
+		"POST /budgets/budget-1/scheduled_transactions",
+	}
+	if strings.Join(requests, ",") != strings.Join(expectedRequests, ",") {
+		t.Errorf("requests = %q, want %q", requests, expectedRequests)
+	}
+	if len(scheduled) != 2 || scheduled[0].Date != "2026-02-23" || scheduled[0].Frequency != "never" || scheduled[0].Amount != -15000000 {
+		t.Errorf("scheduled = %+v, want the second and third installments", scheduled)
+	}
+	if !strings.Contains(dest, "2 scheduled") {
+		t.Errorf("Write() = %q, want the scheduled count", dest)
+	}
+
+	// Uploading the export again (e.g. watch mode) schedules nothing new
+	requests = nil
+	dest, err = sink.Write(parsers.SmbcCard{}, "statement.csv", result)
+	if err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+	if len(scheduled) != 2 {
+		t.Errorf("scheduled %d transaction(s) after the second upload, want 2", len(scheduled))
+	}
+	if want := "POST /budgets/budget-1/transactions,GET /budgets/budget-1/scheduled_transactions"; strings.Join(requests, ",") != want {
+		t.Errorf("requests = %q, want %q", requests, want)
+	}
+	if !strings.Contains(dest, "2 already scheduled") {
+		t.Errorf("Write() = %q, want the already scheduled count", dest)
+	}
+}
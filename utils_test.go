@@ -5,120 +5,6 @@ import (
 	"testing"
 )
 
-func TestFlipSign(t *testing.T) {
-	tests := []struct {
-		name     string
-		input    string
-		expected string
-	}{
-		{"positive number", "1000", "-1000"},
-		{"negative number", "-500", "500"},
-		{"zero", "0", "0"},
-		{"number with comma", "1,000", "-1000"},
-		{"number with multiple commas", "1,000,000", "-1000000"},
-		{"invalid input", "abc", "0"}, // Returns "0" on error
-		{"empty string", "", "0"},     // Returns "0" for empty strings
-		{"large number", "999999999", "-999999999"},
-		{"decimal number", "1234.56", "-1234"}, // Parses as float, truncates to int
-		{"negative decimal", "-789.99", "789"}, // Handles negative floats
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := flipSign(tt.input)
-			if got != tt.expected {
-				t.Errorf("flipSign(%q) = %q, want %q", tt.input, got, tt.expected)
-			}
-		})
-	}
-}
-
-func TestConvertDate(t *testing.T) {
-	tests := []struct {
-		name       string
-		fromLayout string
-		toLayout   string
-		value      string
-		expected   string
-		wantErr    bool
-	}{
-		{
-			name:       "standard format with single digits",
-			fromLayout: "2006/1/2",
-			toLayout:   "2006-01-02",
-			value:      "2024/1/15",
-			expected:   "2024-01-15",
-			wantErr:    false,
-		},
-		{
-			name:       "compact format",
-			fromLayout: "20060102",
-			toLayout:   "2006-01-02",
-			value:      "20240115",
-			expected:   "2024-01-15",
-			wantErr:    false,
-		},
-		{
-			name:       "Japanese format",
-			fromLayout: "2006年01月02日",
-			toLayout:   "2006-01-02",
-			value:      "2024年01月15日",
-			expected:   "2024-01-15",
-			wantErr:    false,
-		},
-		{
-			name:       "format with padded digits",
-			fromLayout: "2006/01/02",
-			toLayout:   "2006-01-02",
-			value:      "2024/01/15",
-			expected:   "2024-01-15",
-			wantErr:    false,
-		},
-		{
-			name:       "leap year date",
-			fromLayout: "2006/1/2",
-			toLayout:   "2006-01-02",
-			value:      "2024/2/29",
-			expected:   "2024-02-29",
-			wantErr:    false,
-		},
-		{
-			name:       "invalid date format",
-			fromLayout: "2006-01-02",
-			toLayout:   "2006-01-02",
-			value:      "invalid",
-			expected:   "",
-			wantErr:    true,
-		},
-		{
-			name:       "mismatched layout",
-			fromLayout: "2006/01/02",
-			toLayout:   "2006-01-02",
-			value:      "20240115", // compact format doesn't match
-			expected:   "",
-			wantErr:    true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got, err := convertDate(tt.fromLayout, tt.toLayout, tt.value)
-			if tt.wantErr {
-				if err == nil {
-					t.Errorf("convertDate(%q, %q, %q) expected error, got nil", tt.fromLayout, tt.toLayout, tt.value)
-				}
-			} else {
-				if err != nil {
-					t.Errorf("convertDate(%q, %q, %q) unexpected error: %v", tt.fromLayout, tt.toLayout, tt.value, err)
-				}
-				if got != tt.expected {
-					t.Errorf("convertDate(%q, %q, %q) = %q, want %q", tt.fromLayout, tt.toLayout, tt.value, got, tt.expected)
-				}
-			}
-		})
-	}
-}
-
 func TestExpandHomeDir(t *testing.T) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"cppcho.com/ynab_import/sink"
+)
+
+// defaultWatchQuiet is how long a file must go without events before it is
+// parsed, long enough for browsers to finish writing a download
+const defaultWatchQuiet = 2 * time.Second
+
+// Suffixes of downloads that browsers are still writing: Chrome and Edge
+// (.crdownload), Firefox (.part), Safari (.download) and others
+var tempDownloadSuffixes = []string{".crdownload", ".part", ".partial", ".download", ".tmp"}
+
+func isTempDownload(name string) bool {
+	for _, suffix := range tempDownloadSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// eventSource is the part of fsnotify.Watcher that watching uses, so tests
+// can replace it
+type eventSource interface {
+	Add(name string) error
+	Events() <-chan fsnotify.Event
+	Errors() <-chan error
+	Close() error
+}
+
+type fsnotifySource struct {
+	*fsnotify.Watcher
+}
+
+func (s fsnotifySource) Events() <-chan fsnotify.Event { return s.Watcher.Events }
+
+func (s fsnotifySource) Errors() <-chan error { return s.Watcher.Errors }
+
+// fileWatcher parses a file once it has been quiet for a while, and only
+// when its content changed since it was last parsed
+type fileWatcher struct {
+	source  eventSource
+	inputs  inputs
+	quiet   time.Duration
+	process func(filePath string) error
+
+	timers map[string]*time.Timer // Paths waiting for their quiet period
+	ready  chan string            // Paths whose quiet period passed
+	done   chan struct{}
+	hashes map[string][sha256.Size]byte // Content of the last parse
+}
+
+func newFileWatcher(source eventSource, in inputs, quiet time.Duration, process func(filePath string) error) *fileWatcher {
+	return &fileWatcher{
+		source:  source,
+		inputs:  in,
+		quiet:   quiet,
+		process: process,
+		timers:  map[string]*time.Timer{},
+		ready:   make(chan string),
+		done:    make(chan struct{}),
+		hashes:  map[string][sha256.Size]byte{},
+	}
+}
+
+// run handles events until the source is closed. Files are parsed one at a
+// time on this goroutine, never while another is being written out.
+func (w *fileWatcher) run() error {
+	defer close(w.done)
+	for {
+		select {
+		case event, ok := <-w.source.Events():
+			if !ok {
+				return nil
+			}
+			w.handle(event)
+		case filePath := <-w.ready:
+			delete(w.timers, filePath)
+			if hash, ok := w.changed(filePath); ok {
+				fmt.Printf("\nDetected change: %s\n", filePath)
+				w.processFile(filePath, hash)
+			}
+		case err, ok := <-w.source.Errors():
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "Watcher error: %v\n", err)
+		}
+	}
+}
+
+// handle (re)starts the quiet period of the file an event is about
+func (w *fileWatcher) handle(event fsnotify.Event) {
+	if event.Has(fsnotify.Create) && w.inputs.acceptsDir(event.Name) {
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			if err := w.watchTree(event.Name, true); err != nil {
+				fmt.Fprintf(os.Stderr, "Watcher error: %v\n", err)
+			}
+			return
+		}
+	}
+	if !w.inputs.acceptsFile(event.Name) {
+		return
+	}
+	if event.Has(fsnotify.Remove) {
+		w.forget(event.Name)
+		return
+	}
+	// A rename reports the old name, which changed finds gone, and the new
+	// name as a create
+	if event.Has(fsnotify.Create) || event.Has(fsnotify.Write) || event.Has(fsnotify.Rename) {
+		w.schedule(event.Name)
+	}
+}
+
+func (w *fileWatcher) schedule(filePath string) {
+	if timer, ok := w.timers[filePath]; ok {
+		timer.Reset(w.quiet)
+		return
+	}
+	w.timers[filePath] = time.AfterFunc(w.quiet, func() {
+		select {
+		case w.ready <- filePath:
+		case <-w.done:
+		}
+	})
+}
+
+// watchTree watches dir and, when recursive, its subdirectories. Files
+// already in a directory that appeared while watching (e.g. moved in) get
+// no events of their own, so they are scheduled when schedule is set.
+func (w *fileWatcher) watchTree(dir string, schedule bool) error {
+	var addErr error
+	err := w.inputs.walk(dir, func(subdir string) {
+		if err := w.source.Add(subdir); err != nil && addErr == nil {
+			addErr = fmt.Errorf("failed to watch directory %q: %w", subdir, err)
+		}
+	}, func(filePath string) {
+		if schedule {
+			w.schedule(filePath)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read input directory %q: %w", dir, err)
+	}
+	return addErr
+}
+
+func (w *fileWatcher) forget(filePath string) {
+	if timer, ok := w.timers[filePath]; ok {
+		timer.Stop()
+		delete(w.timers, filePath)
+	}
+	delete(w.hashes, filePath)
+}
+
+// changed reports whether filePath should be parsed: it is still there,
+// done downloading and its content differs from the last time it was
+// processed. It returns the hash of the content for processFile.
+func (w *fileWatcher) changed(filePath string) ([sha256.Size]byte, bool) {
+	var hash [sha256.Size]byte
+	info, err := os.Stat(filePath)
+	if err != nil || info.IsDir() || info.Size() == 0 {
+		return hash, false // Renamed away, or the empty placeholder of a download
+	}
+	for _, suffix := range tempDownloadSuffixes {
+		if _, err := os.Stat(filePath + suffix); err == nil {
+			return hash, false // Firefox writes next to the final name, which gets an event when done
+		}
+	}
+
+	hash, err = hashFile(filePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error processing %s: %v\n", filePath, err)
+		return hash, false
+	}
+	if previous, ok := w.hashes[filePath]; ok && previous == hash {
+		return hash, false
+	}
+	return hash, true
+}
+
+// processFile processes filePath and only then remembers its content, so a
+// file that failed (e.g. an upload error) is tried again on its next event
+func (w *fileWatcher) processFile(filePath string, hash [sha256.Size]byte) {
+	if err := w.process(filePath); err != nil {
+		fmt.Fprintf(os.Stderr, "Error processing %s: %v\n", filePath, err)
+		return
+	}
+	w.hashes[filePath] = hash
+}
+
+func hashFile(filePath string) ([sha256.Size]byte, error) {
+	var hash [sha256.Size]byte
+	f, err := os.Open(filePath)
+	if err != nil {
+		return hash, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return hash, err
+	}
+	copy(hash[:], h.Sum(nil))
+	return hash, nil
+}
+
+// watchMode processes the exports already in the input directories, then
+// new and changed ones as they settle
+func watchMode(in inputs, quiet time.Duration, out sink.Sink) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create watcher: %w", err)
+	}
+	return watch(fsnotifySource{watcher}, in, quiet, out)
+}
+
+func watch(source eventSource, in inputs, quiet time.Duration, out sink.Sink) error {
+	defer source.Close()
+
+	w := newFileWatcher(source, in, quiet, func(filePath string) error {
+		return processFile(filePath, out)
+	})
+
+	// Start watching before the existing files so none slips through
+	for _, dir := range in.Dirs {
+		if err := w.watchTree(dir, false); err != nil {
+			return err
+		}
+	}
+
+	// Events for the existing files queue up meanwhile, and find them unchanged
+	files, err := in.files()
+	if err != nil {
+		return err
+	}
+	fmt.Println("Processing existing files...")
+	for _, filePath := range files {
+		if hash, ok := w.changed(filePath); ok {
+			w.processFile(filePath, hash)
+		}
+	}
+
+	fmt.Printf("Watching %s for new or changed CSV files... (Press Ctrl+C to stop)\n", strings.Join(in.Dirs, ", "))
+	return w.run()
+}
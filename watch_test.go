@@ -0,0 +1,303 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"cppcho.com/ynab_import/parsers"
+)
+
+// fakeSource is an eventSource fed by the test
+type fakeSource struct {
+	events chan fsnotify.Event
+	errors chan error
+	added  []string
+}
+
+func newFakeSource() *fakeSource {
+	return &fakeSource{events: make(chan fsnotify.Event, 100), errors: make(chan error)}
+}
+
+func (s *fakeSource) Add(name string) error {
+	s.added = append(s.added, name)
+	return nil
+}
+
+func (s *fakeSource) Events() <-chan fsnotify.Event { return s.events }
+
+func (s *fakeSource) Errors() <-chan error { return s.errors }
+
+func (s *fakeSource) Close() error { return nil }
+
+const testQuiet = 20 * time.Millisecond
+
+// runWatcher feeds steps to a fileWatcher and returns the paths it parsed.
+// Each step sends its events at once, then waits for the watcher to settle.
+func runWatcher(t *testing.T, w *fileWatcher, source *fakeSource, steps ...func() []fsnotify.Event) []string {
+	t.Helper()
+	var parsed []string
+	w.process = func(filePath string) error {
+		parsed = append(parsed, filepath.Base(filePath))
+		return nil
+	}
+
+	done := make(chan error)
+	go func() { done <- w.run() }()
+	for _, step := range steps {
+		for _, event := range step() {
+			source.events <- event
+		}
+		time.Sleep(10 * testQuiet)
+	}
+	close(source.events)
+	if err := <-done; err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	return parsed
+}
+
+func writeFile(t *testing.T, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileWatcher_DebouncesChunkedWrites(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "smbc.csv")
+	source := newFakeSource()
+
+	parsed := runWatcher(t, newFileWatcher(source, inputs{Dirs: []string{dir}}, testQuiet, nil), source, func() []fsnotify.Event {
+		writeFile(t, file, "年月日,お引出し\n")
+		writeFile(t, file, "年月日,お引出し\n2025/12/1,100\n")
+		return []fsnotify.Event{
+			{Name: file, Op: fsnotify.Create},
+			{Name: file, Op: fsnotify.Write},
+			{Name: file, Op: fsnotify.Write},
+			{Name: file, Op: fsnotify.Chmod},
+		}
+	})
+
+	if want := []string{"smbc.csv"}; !reflect.DeepEqual(parsed, want) {
+		t.Errorf("parsed %v, want %v", parsed, want)
+	}
+}
+
+func TestFileWatcher_BrowserDownloads(t *testing.T) {
+	dir := t.TempDir()
+	source := newFakeSource()
+
+	chrome := filepath.Join(dir, "chrome.csv")
+	firefox := filepath.Join(dir, "firefox.csv")
+	parsed := runWatcher(t, newFileWatcher(source, inputs{Dirs: []string{dir}}, testQuiet, nil), source,
+		func() []fsnotify.Event {
+			// Chrome writes chrome.csv.crdownload, Firefox an empty firefox.csv next to firefox.csv.part
+			writeFile(t, chrome+".crdownload", "partial")
+			writeFile(t, firefox, "")
+			writeFile(t, firefox+".part", "partial")
+			return []fsnotify.Event{
+				{Name: chrome + ".crdownload", Op: fsnotify.Create},
+				{Name: chrome + ".crdownload", Op: fsnotify.Write},
+				{Name: firefox, Op: fsnotify.Create},
+				{Name: firefox + ".part", Op: fsnotify.Create},
+				{Name: firefox + ".part", Op: fsnotify.Write},
+			}
+		},
+		func() []fsnotify.Event {
+			// Both rename the finished download to its final name
+			if err := os.Rename(chrome+".crdownload", chrome); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Rename(firefox+".part", firefox); err != nil {
+				t.Fatal(err)
+			}
+			return []fsnotify.Event{
+				{Name: chrome + ".crdownload", Op: fsnotify.Rename},
+				{Name: chrome, Op: fsnotify.Create},
+				{Name: firefox + ".part", Op: fsnotify.Rename},
+				{Name: firefox, Op: fsnotify.Rename},
+			}
+		},
+	)
+
+	sort.Strings(parsed)
+	if want := []string{"chrome.csv", "firefox.csv"}; !reflect.DeepEqual(parsed, want) {
+		t.Errorf("parsed %v, want %v", parsed, want)
+	}
+}
+
+func TestFileWatcher_OnlyParsesChangedContent(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "smbc.csv")
+	writeFile(t, file, "v1")
+	source := newFakeSource()
+	w := newFileWatcher(source, inputs{Dirs: []string{dir}}, testQuiet, func(string) error { return nil })
+	hash, _ := w.changed(file)
+	w.processFile(file, hash) // Parsed at startup
+
+	written := func(content string) func() []fsnotify.Event {
+		return func() []fsnotify.Event {
+			writeFile(t, file, content)
+			return []fsnotify.Event{{Name: file, Op: fsnotify.Write}}
+		}
+	}
+	parsed := runWatcher(t, w, source, written("v1"), written("v2"), written("v2"), func() []fsnotify.Event {
+		os.Remove(file)
+		return []fsnotify.Event{{Name: file, Op: fsnotify.Remove}}
+	}, written("v2"))
+
+	// v2 is parsed again after the file was removed and downloaded again
+	if want := []string{"smbc.csv", "smbc.csv"}; !reflect.DeepEqual(parsed, want) {
+		t.Errorf("parsed %v, want %v", parsed, want)
+	}
+}
+
+func TestFileWatcher_RetriesFailedFiles(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "smbc.csv")
+	source := newFakeSource()
+	w := newFileWatcher(source, inputs{Dirs: []string{dir}}, testQuiet, nil)
+
+	attempts := 0
+	var parsed []string
+	done := make(chan error)
+	w.process = func(filePath string) error {
+		attempts++
+		if attempts == 1 {
+			return fmt.Errorf("YNAB API returned 503")
+		}
+		parsed = append(parsed, filepath.Base(filePath))
+		return nil
+	}
+	go func() { done <- w.run() }()
+
+	// The same content again, e.g. the file saved or downloaded once more
+	for _, step := range []string{"v1", "v1", "v1"} {
+		writeFile(t, file, step)
+		source.events <- fsnotify.Event{Name: file, Op: fsnotify.Write}
+		time.Sleep(10 * testQuiet)
+	}
+	close(source.events)
+	if err := <-done; err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	// Failed, then retried, then unchanged
+	if attempts != 2 || !reflect.DeepEqual(parsed, []string{"smbc.csv"}) {
+		t.Errorf("processed %d time(s), parsed %v; want the failed file retried once", attempts, parsed)
+	}
+}
+
+// countingSink counts the files written through it
+type countingSink struct {
+	written *int
+}
+
+func (s countingSink) Write(parser parsers.Source, fileName string, result *parsers.ParseResult) (string, error) {
+	*s.written++
+	return fileName, nil
+}
+
+func TestWatch_ProcessesExistingFiles(t *testing.T) {
+	inputDir := t.TempDir()
+	data, err := os.ReadFile("parsers/testdata/smbc_valid.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	existing := filepath.Join(inputDir, "smbc_test.csv")
+	writeFile(t, existing, string(data))
+	source := newFakeSource()
+	// A write without changes after startup must not convert the file again
+	source.events <- fsnotify.Event{Name: existing, Op: fsnotify.Write}
+	go func() {
+		time.Sleep(10 * testQuiet)
+		close(source.events)
+	}()
+
+	written := 0
+	if err := watch(source, inputs{Dirs: []string{inputDir}}, testQuiet, countingSink{&written}); err != nil {
+		t.Fatalf("watch() error = %v", err)
+	}
+	if want := []string{inputDir}; !reflect.DeepEqual(source.added, want) {
+		t.Errorf("watched %v, want %v", source.added, want)
+	}
+	if written != 1 {
+		t.Errorf("wrote %d file(s), want 1", written)
+	}
+}
+
+func TestFileWatcher_WatchesNewSubdirectories(t *testing.T) {
+	dir := t.TempDir()
+	source := newFakeSource()
+	w := newFileWatcher(source, inputs{Dirs: []string{dir}, Recursive: true, Exclude: []string{"archive"}}, testQuiet, nil)
+
+	bank := filepath.Join(dir, "smbc")
+	parsed := runWatcher(t, w, source, func() []fsnotify.Event {
+		// A folder moved in with its files and subfolders only reports itself
+		for _, name := range []string{"smbc/2025-12.CSV", "smbc/archive/2024-12.csv", "smbc/2025/11.csv"} {
+			filePath := filepath.Join(dir, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+				t.Fatal(err)
+			}
+			writeFile(t, filePath, name)
+		}
+		return []fsnotify.Event{{Name: bank, Op: fsnotify.Create}}
+	}, func() []fsnotify.Event {
+		writeFile(t, filepath.Join(bank, "2026-01.csv"), "new")
+		return []fsnotify.Event{{Name: filepath.Join(bank, "2026-01.csv"), Op: fsnotify.Create}}
+	})
+
+	if want := []string{bank, filepath.Join(bank, "2025")}; !reflect.DeepEqual(source.added, want) {
+		t.Errorf("watched %v, want %v", source.added, want)
+	}
+	sort.Strings(parsed)
+	if want := []string{"11.csv", "2025-12.CSV", "2026-01.csv"}; !reflect.DeepEqual(parsed, want) {
+		t.Errorf("parsed %v, want %v", parsed, want)
+	}
+}
+
+// failingSink fails its first write, like an upload that hits a network error
+type failingSink struct {
+	writes *int
+}
+
+func (s failingSink) Write(parser parsers.Source, fileName string, result *parsers.ParseResult) (string, error) {
+	*s.writes++
+	if *s.writes == 1 {
+		return "", fmt.Errorf("YNAB request failed")
+	}
+	return fileName, nil
+}
+
+func TestWatch_RetriesExistingFilesThatFailed(t *testing.T) {
+	inputDir := t.TempDir()
+	data, err := os.ReadFile("parsers/testdata/smbc_valid.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	existing := filepath.Join(inputDir, "smbc_test.csv")
+	writeFile(t, existing, string(data))
+	source := newFakeSource()
+	// Processing at startup fails, so the next event tries again
+	source.events <- fsnotify.Event{Name: existing, Op: fsnotify.Write}
+	go func() {
+		time.Sleep(10 * testQuiet)
+		close(source.events)
+	}()
+
+	writes := 0
+	if err := watch(source, inputs{Dirs: []string{inputDir}}, testQuiet, failingSink{&writes}); err != nil {
+		t.Fatalf("watch() error = %v", err)
+	}
+	if writes != 2 {
+		t.Errorf("wrote %d time(s), want 2", writes)
+	}
+}